@@ -0,0 +1,12 @@
+package alert
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var AlertCmd = &cobra.Command{
+	Use:   "alert",
+	Short: "Configure and check budget threshold alerts",
+	Long: "Budgets warn when spending crosses their thresholds (80% and 100% by default). " +
+		"Newly crossed thresholds can also be sent to a desktop notification command, a webhook or email.",
+}
@@ -0,0 +1,39 @@
+package alert
+
+import (
+	"fmt"
+	"time"
+
+	"personal-finance-cli/db"
+	"personal-finance-cli/internal/alert"
+
+	"github.com/spf13/cobra"
+)
+
+var CheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Evaluate every budget's current period against its thresholds",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgets, err := db.GetBudgets()
+		if err != nil {
+			return err
+		}
+		var touched []alert.Touch
+		for _, b := range budgets {
+			touched = append(touched, alert.Touch{Category: b.Category, Date: time.Now()})
+		}
+
+		lines := alert.Warnings(alert.Evaluate(touched...))
+		if len(lines) == 0 {
+			fmt.Println("All budgets are below their thresholds.")
+		}
+		for _, l := range lines {
+			fmt.Println(l)
+		}
+		return nil
+	},
+}
+
+func init() {
+	AlertCmd.AddCommand(CheckCmd)
+}
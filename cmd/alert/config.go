@@ -0,0 +1,70 @@
+package alert
+
+import (
+	"fmt"
+
+	"personal-finance-cli/db"
+	"personal-finance-cli/internal/alert"
+
+	"github.com/spf13/cobra"
+)
+
+var configFlags = map[string]string{
+	"command":       alert.CommandKey,
+	"webhook":       alert.WebhookKey,
+	"smtp-addr":     alert.SMTPAddrKey,
+	"smtp-from":     alert.SMTPFromKey,
+	"smtp-to":       alert.SMTPToKey,
+	"smtp-user":     alert.SMTPUserKey,
+	"smtp-password": alert.SMTPPassKey,
+}
+
+var configValues = map[string]*string{}
+
+var ConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show or set alert notifiers (pass an empty value to disable one)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for flag, key := range configFlags {
+			if !cmd.Flags().Changed(flag) {
+				continue
+			}
+			set := db.SetSetting
+			if key == alert.SMTPPassKey {
+				set = db.SetSecretSetting
+			}
+			if err := set(key, *configValues[flag]); err != nil {
+				return err
+			}
+		}
+
+		for _, key := range alert.SettingKeys {
+			v, err := db.GetSetting(key)
+			if err != nil {
+				return err
+			}
+			if key == alert.SMTPPassKey && v != "" {
+				v = "********"
+			}
+			fmt.Printf("%s = %s\n", key, v)
+		}
+		return nil
+	},
+}
+
+func init() {
+	usage := map[string]string{
+		"command":       "Command run with the alert message as last argument, e.g. \"notify-send Budget\"",
+		"webhook":       "URL that receives alerts as a JSON POST",
+		"smtp-addr":     "SMTP server host:port for email alerts",
+		"smtp-from":     "Sender address for email alerts",
+		"smtp-to":       "Comma-separated recipients for email alerts",
+		"smtp-user":     "SMTP username (optional)",
+		"smtp-password": "SMTP password (optional)",
+	}
+	for flag := range configFlags {
+		configValues[flag] = ConfigCmd.Flags().String(flag, "", usage[flag])
+	}
+
+	AlertCmd.AddCommand(ConfigCmd)
+}
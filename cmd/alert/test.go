@@ -0,0 +1,40 @@
+package alert
+
+import (
+	"fmt"
+	"time"
+
+	"personal-finance-cli/internal/alert"
+
+	"github.com/spf13/cobra"
+)
+
+var TestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Send a sample alert to every configured notifier",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		notifiers, err := alert.ConfiguredNotifiers()
+		if err != nil {
+			return err
+		}
+		if len(notifiers) == 0 {
+			fmt.Println("No notifiers configured.")
+			return nil
+		}
+
+		today := time.Now().Format("2006-01-02")
+		sample := alert.Alert{Category: "Test", From: today, To: today, Threshold: 100, PctUsed: 100, Spent: 1, Available: 1, New: true}
+		for _, n := range notifiers {
+			if err := n.Notify(sample); err != nil {
+				fmt.Printf("%s: %v\n", n.Name(), err)
+				continue
+			}
+			fmt.Printf("%s: sent\n", n.Name())
+		}
+		return nil
+	},
+}
+
+func init() {
+	AlertCmd.AddCommand(TestCmd)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"personal-finance-cli/db"
+	"personal-finance-cli/internal/period"
 
 	"github.com/spf13/cobra"
 )
@@ -13,6 +14,10 @@ var (
 	addCategory string
 	addAmount   float64
 	addPeriod   string
+	addRollover string
+	addStart    string
+	addAlerts   string
+	addKind     string
 )
 
 var AddCmd = &cobra.Command{
@@ -22,10 +27,27 @@ var AddCmd = &cobra.Command{
 		if addPeriod == "" {
 			addPeriod = time.Now().Format("2006-01")
 		}
+		if _, err := period.Parse(addPeriod); err != nil {
+			return err
+		}
 		b := db.Budget{
 			Category: addCategory,
 			Amount:   addAmount,
 			Period:   addPeriod,
+			Kind:     addKind,
+			Rollover: addRollover,
+		}
+		thresholds, err := db.ParseThresholds(addAlerts)
+		if err != nil {
+			return err
+		}
+		b.Thresholds = thresholds
+		if addStart != "" {
+			start, err := time.Parse("2006-01-02", addStart)
+			if err != nil {
+				return fmt.Errorf("invalid start date: %w", err)
+			}
+			b.Start = start
 		}
 		if err := db.InsertBudget(b); err != nil {
 			return err
@@ -38,7 +60,11 @@ var AddCmd = &cobra.Command{
 func init() {
 	AddCmd.Flags().StringVarP(&addCategory, "category", "c", "", "Category (required)")
 	AddCmd.Flags().Float64VarP(&addAmount, "amount", "a", 0, "Budget amount (required)")
-	AddCmd.Flags().StringVarP(&addPeriod, "period", "p", "", "Period YYYY-MM (optional; defaults to current month)")
+	AddCmd.Flags().StringVarP(&addPeriod, "period", "p", "", "Period "+periodFlagUsage+" (optional; defaults to current month)")
+	AddCmd.Flags().StringVarP(&addKind, "kind", "k", db.KindExpense, kindFlagUsage)
+	AddCmd.Flags().StringVarP(&addRollover, "rollover", "r", db.RolloverReset, rolloverFlagUsage)
+	AddCmd.Flags().StringVarP(&addStart, "start", "s", "", "Start date YYYY-MM-DD a recurring budget accumulates rollover from (defaults to today)")
+	AddCmd.Flags().StringVar(&addAlerts, "alerts", "80,100", alertsFlagUsage)
 	_ = AddCmd.MarkFlagRequired("category")
 	_ = AddCmd.MarkFlagRequired("amount")
 
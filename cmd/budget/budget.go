@@ -4,6 +4,13 @@ import (
 	"github.com/spf13/cobra"
 )
 
+const (
+	periodFlagUsage   = "YYYY-MM, weekly, monthly, quarterly, yearly or YYYY-MM-DD..YYYY-MM-DD"
+	rolloverFlagUsage = "What a recurring budget carries into the next period: reset, unspent, overspend or both"
+	kindFlagUsage     = "Budget kind: expense (spending limit), income (income target) or savings (net savings goal)"
+	alertsFlagUsage   = "Comma-separated percentages of the budget that trigger alerts (empty disables alerts)"
+)
+
 var BudgetCmd = &cobra.Command{
 	Use:   "budget",
 	Short: "Manage budgets",
@@ -0,0 +1,56 @@
+package budget
+
+import (
+	"fmt"
+	"strings"
+
+	"personal-finance-cli/db"
+	"personal-finance-cli/internal/period"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	copyFrom   string
+	copyTo     string
+	copyAdjust float64
+)
+
+var CopyCmd = &cobra.Command{
+	Use:   "copy",
+	Short: "Copy every budget of one period to another, optionally adjusted by a percentage",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, err := period.Parse(copyFrom)
+		if err != nil {
+			return err
+		}
+		items, err := db.BudgetItemsForPeriod(from.String())
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			fmt.Printf("No budgets found for %s.\n", from)
+			return nil
+		}
+
+		created, skipped, err := db.ApplyBudgetItems(items, copyTo, copyAdjust)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Copied %d budget(s) to %s.\n", created, copyTo)
+		if len(skipped) > 0 {
+			fmt.Printf("Skipped (already budgeted): %s\n", strings.Join(skipped, ", "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	CopyCmd.Flags().StringVar(&copyFrom, "from", "", "Source period (required)")
+	CopyCmd.Flags().StringVar(&copyTo, "to", "", "Target period (required)")
+	CopyCmd.Flags().Float64Var(&copyAdjust, "adjust", 0, "Percentage to change every amount by, e.g. 5 or -10")
+	_ = CopyCmd.MarkFlagRequired("from")
+	_ = CopyCmd.MarkFlagRequired("to")
+
+	BudgetCmd.AddCommand(CopyCmd)
+}
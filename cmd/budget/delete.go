@@ -16,7 +16,7 @@ var DeleteCmd = &cobra.Command{
 		if err := db.DeleteBudget(deleteID); err != nil {
 			return err
 		}
-		fmt.Println("Budget deleted.")
+		fmt.Printf("Budget moved to the trash. Run \"trash restore --type budget --id %d\" or \"undo\" to bring it back.\n", deleteID)
 		return nil
 	},
 }
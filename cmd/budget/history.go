@@ -0,0 +1,63 @@
+package budget
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyID     int
+	historyFormat string
+)
+
+var HistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show a budget period by period with the balance carried between periods",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, err := db.GetBudgetByID(historyID)
+		if err != nil {
+			return err
+		}
+		if b == nil {
+			fmt.Printf("Budget with ID %d not found.\n", historyID)
+			return nil
+		}
+
+		history, err := db.GetBudgetHistory(*b, time.Now())
+		if err != nil {
+			return err
+		}
+
+		switch historyFormat {
+		case "json":
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(history)
+		case "table":
+		default:
+			return fmt.Errorf("unknown format %q (use table or json)", historyFormat)
+		}
+
+		fmt.Printf("Budget %d: %s %s, %.2f %s, rollover %s\n", b.ID, b.Kind, b.Category, b.Amount, b.Period, b.Rollover)
+		fmt.Println("From | To | Amount | Carried in | Available | Actual | Balance")
+		for _, o := range history {
+			fmt.Printf("%s | %s | %.2f | %.2f | %.2f | %.2f | %.2f\n",
+				o.From, o.To, o.Limit, o.CarriedIn, o.Available, o.Actual, o.Balance)
+		}
+		return nil
+	},
+}
+
+func init() {
+	HistoryCmd.Flags().IntVarP(&historyID, "id", "i", 0, "ID of budget (required)")
+	HistoryCmd.Flags().StringVarP(&historyFormat, "format", "f", "table", "Output format: table or json")
+	_ = HistoryCmd.MarkFlagRequired("id")
+
+	BudgetCmd.AddCommand(HistoryCmd)
+}
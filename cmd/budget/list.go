@@ -3,6 +3,7 @@ package budget
 import (
 	"fmt"
 	"personal-finance-cli/db"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -38,9 +39,9 @@ var ListCmd = &cobra.Command{
 			return nil
 		}
 
-		fmt.Println("ID | Category | Amount | Period")
+		fmt.Println("ID | Kind | Category | Amount | Period | Rollover | Alerts")
 		for _, b := range budgets {
-			fmt.Printf("%d | %s | %.2f | %s\n", b.ID, b.Category, b.Amount, b.Period)
+			fmt.Printf("%d | %s | %s | %.2f | %s | %s | %s\n", b.ID, b.Kind, b.Category, b.Amount, b.Period, b.Rollover, formatAlerts(b.Thresholds))
 		}
 		return nil
 	},
@@ -50,3 +51,14 @@ func init() {
 	ListCmd.Flags().IntVarP(&listID, "id", "i", 0, "ID of budget to list (optional)")
 	BudgetCmd.AddCommand(ListCmd)
 }
+
+func formatAlerts(thresholds []float64) string {
+	if len(thresholds) == 0 {
+		return "off"
+	}
+	parts := make([]string, len(thresholds))
+	for i, t := range thresholds {
+		parts[i] = fmt.Sprintf("%g%%", t)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,103 @@
+package budget
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"personal-finance-cli/db"
+	"personal-finance-cli/internal/period"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	colorReset  = "\033[0m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorRed    = "\033[31m"
+)
+
+var (
+	statusPeriod string
+	statusFormat string
+)
+
+var StatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show progress of each budget, plus income, expenses and savings rate",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		window := period.MonthOf(time.Now())
+		if statusPeriod != "" {
+			p, err := period.Parse(statusPeriod)
+			if err != nil {
+				return err
+			}
+			window = p
+		}
+
+		report, err := db.GetBudgetReport(window, time.Now())
+		if err != nil {
+			return err
+		}
+
+		switch statusFormat {
+		case "json":
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(report)
+		case "table":
+		default:
+			return fmt.Errorf("unknown format %q (use table or json)", statusFormat)
+		}
+
+		label := fmt.Sprintf("%s to %s", report.From, report.To)
+		if len(report.Budgets) == 0 {
+			fmt.Printf("No budgets for %s.\n", label)
+		} else {
+			fmt.Printf("Budgets for %s\n", label)
+			fmt.Println("ID | Kind | Category | Period | From | To | Amount | Carried | Actual | Remaining | Progress | Days left | Projected")
+			for _, s := range report.Budgets {
+				fmt.Printf("%d | %s | %s | %s | %s | %s | %.2f | %.2f | %.2f | %.2f | %s%.0f%%%s | %d | %.2f\n",
+					s.Budget.ID, s.Budget.Kind, s.Budget.Category, s.Budget.Period, s.From, s.To, s.Budget.Amount, s.Carried, s.Actual, s.Remaining,
+					progressColor(s), s.PctUsed, colorReset, s.DaysLeft, s.Projected)
+			}
+		}
+
+		fmt.Printf("Income %.2f | Expenses %.2f | Saved %.2f | Savings rate %.1f%%\n",
+			report.Income, report.Expenses, report.Saved, report.SavingsRate*100)
+		return nil
+	},
+}
+
+// progressColor colours spending limits green below 80% used, yellow up to
+// the limit and red over it. Income and savings goals are green once reached,
+// red if the period ended short and yellow while still in progress.
+func progressColor(s db.BudgetStatus) string {
+	if s.Budget.Kind != db.KindExpense && s.Budget.Kind != "" {
+		switch {
+		case s.PctUsed >= 100:
+			return colorGreen
+		case s.DaysLeft == 0:
+			return colorRed
+		default:
+			return colorYellow
+		}
+	}
+	switch {
+	case s.PctUsed > 100:
+		return colorRed
+	case s.PctUsed >= 80:
+		return colorYellow
+	default:
+		return colorGreen
+	}
+}
+
+func init() {
+	StatusCmd.Flags().StringVarP(&statusPeriod, "period", "p", "", "Period to report on: YYYY-MM, YYYY-MM-DD..YYYY-MM-DD or weekly/monthly/quarterly/yearly (defaults to current month)")
+	StatusCmd.Flags().StringVarP(&statusFormat, "format", "f", "table", "Output format: table or json")
+
+	BudgetCmd.AddCommand(StatusCmd)
+}
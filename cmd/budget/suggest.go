@@ -0,0 +1,66 @@
+package budget
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	suggestMonths int
+	suggestPeriod string
+	suggestApply  bool
+)
+
+var SuggestCmd = &cobra.Command{
+	Use:   "suggest",
+	Short: "Suggest budget amounts from average monthly spend per category",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if suggestMonths != 3 && suggestMonths != 6 && suggestMonths != 12 {
+			return fmt.Errorf("--months must be 3, 6 or 12")
+		}
+		if suggestPeriod == "" {
+			suggestPeriod = time.Now().Format("2006-01")
+		}
+
+		items, err := db.GetAverageMonthlySpend(suggestMonths, time.Now())
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			fmt.Printf("No expenses in the last %d months to base suggestions on.\n", suggestMonths)
+			return nil
+		}
+
+		fmt.Printf("Suggested budgets for %s (average of the last %d full months)\n", suggestPeriod, suggestMonths)
+		fmt.Println("Category | Amount")
+		for _, it := range items {
+			fmt.Printf("%s | %.2f\n", it.Category, it.Amount)
+		}
+
+		if !suggestApply {
+			return nil
+		}
+		created, skipped, err := db.ApplyBudgetItems(items, suggestPeriod, 0)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created %d budget(s) for %s.\n", created, suggestPeriod)
+		if len(skipped) > 0 {
+			fmt.Printf("Skipped (already budgeted): %s\n", strings.Join(skipped, ", "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	SuggestCmd.Flags().IntVarP(&suggestMonths, "months", "m", 3, "Trailing months to average: 3, 6 or 12")
+	SuggestCmd.Flags().StringVarP(&suggestPeriod, "period", "p", "", "Period to create budgets for with --apply (defaults to current month)")
+	SuggestCmd.Flags().BoolVar(&suggestApply, "apply", false, "Create the suggested budgets")
+
+	BudgetCmd.AddCommand(SuggestCmd)
+}
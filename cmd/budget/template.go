@@ -0,0 +1,14 @@
+package budget
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var TemplateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Manage named budget templates",
+}
+
+func init() {
+	BudgetCmd.AddCommand(TemplateCmd)
+}
@@ -0,0 +1,50 @@
+package budget
+
+import (
+	"fmt"
+	"strings"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	templateApplyName   string
+	templateApplyPeriod string
+	templateApplyAdjust float64
+)
+
+var TemplateApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Create budgets for a period from a named template",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := db.GetBudgetTemplate(templateApplyName)
+		if err != nil {
+			return err
+		}
+		if t == nil {
+			return fmt.Errorf("template %q not found", templateApplyName)
+		}
+
+		created, skipped, err := db.ApplyBudgetItems(t.Items, templateApplyPeriod, templateApplyAdjust)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created %d budget(s) for %s from %q.\n", created, templateApplyPeriod, t.Name)
+		if len(skipped) > 0 {
+			fmt.Printf("Skipped (already budgeted): %s\n", strings.Join(skipped, ", "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	TemplateApplyCmd.Flags().StringVarP(&templateApplyName, "name", "n", "", "Template name (required)")
+	TemplateApplyCmd.Flags().StringVarP(&templateApplyPeriod, "period", "p", "", "Period to create budgets for (required)")
+	TemplateApplyCmd.Flags().Float64Var(&templateApplyAdjust, "adjust", 0, "Percentage to change every amount by, e.g. 5 or -10")
+	_ = TemplateApplyCmd.MarkFlagRequired("name")
+	_ = TemplateApplyCmd.MarkFlagRequired("period")
+
+	TemplateCmd.AddCommand(TemplateApplyCmd)
+}
@@ -0,0 +1,30 @@
+package budget
+
+import (
+	"fmt"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var templateDeleteName string
+
+var TemplateDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a budget template",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := db.DeleteBudgetTemplate(templateDeleteName); err != nil {
+			return err
+		}
+		fmt.Println("Template deleted. Run \"undo\" to restore it.")
+		return nil
+	},
+}
+
+func init() {
+	TemplateDeleteCmd.Flags().StringVarP(&templateDeleteName, "name", "n", "", "Template name (required)")
+	_ = TemplateDeleteCmd.MarkFlagRequired("name")
+
+	TemplateCmd.AddCommand(TemplateDeleteCmd)
+}
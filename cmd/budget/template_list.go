@@ -0,0 +1,40 @@
+package budget
+
+import (
+	"fmt"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var TemplateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List budget templates",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		templates, err := db.GetBudgetTemplates()
+		if err != nil {
+			return err
+		}
+		if len(templates) == 0 {
+			fmt.Println("No templates found.")
+			return nil
+		}
+
+		for _, t := range templates {
+			total := 0.0
+			for _, it := range t.Items {
+				total += it.Amount
+			}
+			fmt.Printf("%s (%d categories, total %.2f)\n", t.Name, len(t.Items), total)
+			for _, it := range t.Items {
+				fmt.Printf("  %s | %s | %.2f\n", it.Category, it.Kind, it.Amount)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	TemplateCmd.AddCommand(TemplateListCmd)
+}
@@ -0,0 +1,47 @@
+package budget
+
+import (
+	"fmt"
+
+	"personal-finance-cli/db"
+	"personal-finance-cli/internal/period"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	templateSaveName   string
+	templateSavePeriod string
+)
+
+var TemplateSaveCmd = &cobra.Command{
+	Use:   "save",
+	Short: "Save the budgets of a period as a named template",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := period.Parse(templateSavePeriod)
+		if err != nil {
+			return err
+		}
+		items, err := db.BudgetItemsForPeriod(p.String())
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return fmt.Errorf("no budgets found for %s", p)
+		}
+		if err := db.SaveBudgetTemplate(templateSaveName, items); err != nil {
+			return err
+		}
+		fmt.Printf("Template %q saved with %d categories.\n", templateSaveName, len(items))
+		return nil
+	},
+}
+
+func init() {
+	TemplateSaveCmd.Flags().StringVarP(&templateSaveName, "name", "n", "", "Template name (required)")
+	TemplateSaveCmd.Flags().StringVarP(&templateSavePeriod, "period", "p", "", "Period whose budgets to save (required)")
+	_ = TemplateSaveCmd.MarkFlagRequired("name")
+	_ = TemplateSaveCmd.MarkFlagRequired("period")
+
+	TemplateCmd.AddCommand(TemplateSaveCmd)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"personal-finance-cli/db"
+	"personal-finance-cli/internal/period"
 
 	"github.com/spf13/cobra"
 )
@@ -14,24 +15,58 @@ var (
 	updateCategory string
 	updateAmount   float64
 	updatePeriod   string
+	updateRollover string
+	updateStart    string
+	updateAlerts   string
+	updateKind     string
 )
 
 var UpdateCmd = &cobra.Command{
 	Use:   "update",
 	Short: "Update a budget by ID",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		b := db.Budget{
-			ID:       updateID,
-			Category: updateCategory,
-			Amount:   updateAmount,
-			Period:   updatePeriod,
+		b, err := db.GetBudgetByID(updateID)
+		if err != nil {
+			return err
+		}
+		if b == nil {
+			return fmt.Errorf("budget with ID %d not found", updateID)
 		}
 
-		if b.Period == "" {
-			b.Period = time.Now().Format("2006-01")
+		if cmd.Flags().Changed("category") {
+			b.Category = updateCategory
+		}
+		if cmd.Flags().Changed("amount") {
+			b.Amount = updateAmount
+		}
+		if cmd.Flags().Changed("period") {
+			if _, err := period.Parse(updatePeriod); err != nil {
+				return err
+			}
+			b.Period = updatePeriod
+		}
+		if cmd.Flags().Changed("kind") {
+			b.Kind = updateKind
+		}
+		if cmd.Flags().Changed("rollover") {
+			b.Rollover = updateRollover
+		}
+		if cmd.Flags().Changed("alerts") {
+			thresholds, err := db.ParseThresholds(updateAlerts)
+			if err != nil {
+				return err
+			}
+			b.Thresholds = thresholds
+		}
+		if cmd.Flags().Changed("start") {
+			start, err := time.Parse("2006-01-02", updateStart)
+			if err != nil {
+				return fmt.Errorf("invalid start date: %w", err)
+			}
+			b.Start = start
 		}
 
-		if err := db.UpdateBudget(b); err != nil {
+		if err := db.UpdateBudget(*b); err != nil {
 			return err
 		}
 		fmt.Println("Budget updated.")
@@ -43,7 +78,11 @@ func init() {
 	UpdateCmd.Flags().IntVarP(&updateID, "id", "i", 0, "ID of budget to update (required)")
 	UpdateCmd.Flags().StringVarP(&updateCategory, "category", "c", "", "New category")
 	UpdateCmd.Flags().Float64VarP(&updateAmount, "amount", "a", 0, "New budget amount")
-	UpdateCmd.Flags().StringVarP(&updatePeriod, "period", "p", "", "New period YYYY-MM")
+	UpdateCmd.Flags().StringVarP(&updatePeriod, "period", "p", "", "New period "+periodFlagUsage)
+	UpdateCmd.Flags().StringVarP(&updateKind, "kind", "k", "", kindFlagUsage)
+	UpdateCmd.Flags().StringVarP(&updateRollover, "rollover", "r", "", rolloverFlagUsage)
+	UpdateCmd.Flags().StringVarP(&updateStart, "start", "s", "", "New start date YYYY-MM-DD for rollover accumulation")
+	UpdateCmd.Flags().StringVar(&updateAlerts, "alerts", "", alertsFlagUsage)
 	_ = UpdateCmd.MarkFlagRequired("id")
 
 	BudgetCmd.AddCommand(UpdateCmd)
@@ -0,0 +1,35 @@
+package envelope
+
+import (
+	"fmt"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	assignCategory string
+	assignAmount   float64
+)
+
+var AssignCmd = &cobra.Command{
+	Use:   "assign",
+	Short: "Assign ready-to-assign money to an envelope (negative amounts return it)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := db.AssignToEnvelope(assignCategory, currentMonth(), assignAmount); err != nil {
+			return err
+		}
+		fmt.Println("Envelope updated.")
+		return nil
+	},
+}
+
+func init() {
+	AssignCmd.Flags().StringVarP(&assignCategory, "category", "c", "", "Envelope category (required)")
+	AssignCmd.Flags().Float64VarP(&assignAmount, "amount", "a", 0, "Amount to assign (required)")
+	_ = AssignCmd.MarkFlagRequired("category")
+	_ = AssignCmd.MarkFlagRequired("amount")
+
+	EnvelopeCmd.AddCommand(AssignCmd)
+}
@@ -0,0 +1,36 @@
+package envelope
+
+import (
+	"fmt"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	coverCategory string
+	coverFrom     string
+)
+
+var CoverCmd = &cobra.Command{
+	Use:   "cover",
+	Short: "Cover an overspent envelope from another envelope",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		amount, err := db.CoverOverspending(coverCategory, coverFrom, currentMonth())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Covered %.2f of %s from %s.\n", amount, coverCategory, coverFrom)
+		return nil
+	},
+}
+
+func init() {
+	CoverCmd.Flags().StringVarP(&coverCategory, "category", "c", "", "Overspent envelope (required)")
+	CoverCmd.Flags().StringVar(&coverFrom, "from", "", "Envelope to take the money from (required)")
+	_ = CoverCmd.MarkFlagRequired("category")
+	_ = CoverCmd.MarkFlagRequired("from")
+
+	EnvelopeCmd.AddCommand(CoverCmd)
+}
@@ -0,0 +1,26 @@
+package envelope
+
+import (
+	"fmt"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var EnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Start envelope budgeting from a month",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		month := currentMonth()
+		if err := db.EnableEnvelopes(month); err != nil {
+			return err
+		}
+		fmt.Printf("Envelope mode enabled from %s.\n", month)
+		return nil
+	},
+}
+
+func init() {
+	EnvelopeCmd.AddCommand(EnableCmd)
+}
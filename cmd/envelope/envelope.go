@@ -0,0 +1,27 @@
+package envelope
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var EnvelopeCmd = &cobra.Command{
+	Use:   "envelope",
+	Short: "Zero-based envelope budgeting",
+	Long: "Income funds a ready-to-assign pool; assign it to category envelopes, move money between them " +
+		"and cover overspent envelopes from others.",
+}
+
+var envelopeMonth string
+
+func currentMonth() string {
+	if envelopeMonth != "" {
+		return envelopeMonth
+	}
+	return time.Now().Format("2006-01")
+}
+
+func init() {
+	EnvelopeCmd.PersistentFlags().StringVarP(&envelopeMonth, "month", "m", "", "Month YYYY-MM (optional; defaults to current month)")
+}
@@ -0,0 +1,38 @@
+package envelope
+
+import (
+	"fmt"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	moveFrom   string
+	moveTo     string
+	moveAmount float64
+)
+
+var MoveCmd = &cobra.Command{
+	Use:   "move",
+	Short: "Move money from one envelope to another",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := db.MoveBetweenEnvelopes(moveFrom, moveTo, currentMonth(), moveAmount); err != nil {
+			return err
+		}
+		fmt.Printf("Moved %.2f from %s to %s.\n", moveAmount, moveFrom, moveTo)
+		return nil
+	},
+}
+
+func init() {
+	MoveCmd.Flags().StringVar(&moveFrom, "from", "", "Source envelope (required)")
+	MoveCmd.Flags().StringVar(&moveTo, "to", "", "Destination envelope (required)")
+	MoveCmd.Flags().Float64VarP(&moveAmount, "amount", "a", 0, "Amount to move (required)")
+	_ = MoveCmd.MarkFlagRequired("from")
+	_ = MoveCmd.MarkFlagRequired("to")
+	_ = MoveCmd.MarkFlagRequired("amount")
+
+	EnvelopeCmd.AddCommand(MoveCmd)
+}
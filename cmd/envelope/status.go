@@ -0,0 +1,48 @@
+package envelope
+
+import (
+	"fmt"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var StatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show ready-to-assign and every envelope's balance",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		month := currentMonth()
+		ready, err := db.ReadyToAssign(month)
+		if err != nil {
+			return err
+		}
+		envelopes, err := db.GetEnvelopes(month)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Ready to assign (%s): %.2f\n", month, ready)
+		if len(envelopes) == 0 {
+			fmt.Println("No envelopes yet.")
+			return nil
+		}
+
+		fmt.Println("Category | Assigned | Spent | Balance")
+		var overspent []string
+		for _, e := range envelopes {
+			fmt.Printf("%s | %.2f | %.2f | %.2f\n", e.Category, e.Assigned, e.Spent, e.Balance)
+			if e.Overspent() {
+				overspent = append(overspent, e.Category)
+			}
+		}
+		for _, c := range overspent {
+			fmt.Printf("Warning: %s is overspent; cover it with \"envelope cover --category %s --from <envelope>\".\n", c, c)
+		}
+		return nil
+	},
+}
+
+func init() {
+	EnvelopeCmd.AddCommand(StatusCmd)
+}
@@ -0,0 +1,53 @@
+package history
+
+import (
+	"fmt"
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyLimit   int
+	historyVerbose bool
+)
+
+var HistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List recent changes to your data, newest first",
+	Long: "Every add, update and delete of transactions, budgets (including envelope assignments), budget templates, holdings " +
+		"and valuations, recurring templates and settings is logged with the values before and after, when it " +
+		"happened and where it came from (cli, tui, import, recurring or rule). Use \"undo\" and \"redo\" to step " +
+		"through it. UI state such as the transaction table's sort order and fired alerts are not logged.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		changes, err := db.GetHistory(historyLimit)
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			fmt.Println("No changes recorded.")
+			return nil
+		}
+
+		fmt.Println("ID | When | Origin | Change")
+		for _, c := range changes {
+			status := ""
+			if c.Undone {
+				status = " [undone]"
+			}
+			fmt.Printf("%d | %s | %s | %s (%d row(s))%s\n", c.ID, c.At.Local().Format("2006-01-02 15:04"), c.Origin,
+				c.Summary, len(c.Entries), status)
+			if historyVerbose {
+				for _, e := range c.Entries {
+					fmt.Printf("    %s %d %s: %s\n", e.Table, e.RowID, e.Action(), e.Diff())
+				}
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	HistoryCmd.Flags().IntVarP(&historyLimit, "limit", "n", 20, "Number of changes to show")
+	HistoryCmd.Flags().BoolVarP(&historyVerbose, "verbose", "v", false, "Show the old and new values of every row")
+}
@@ -0,0 +1,25 @@
+package history
+
+import (
+	"fmt"
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var RedoCmd = &cobra.Command{
+	Use:   "redo",
+	Short: "Re-apply the change that was undone last",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := db.Redo()
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			fmt.Println("Nothing to redo.")
+			return nil
+		}
+		fmt.Printf("Redid: %s (%d row(s)).\n", c.Summary, len(c.Entries))
+		return nil
+	},
+}
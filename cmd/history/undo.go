@@ -0,0 +1,25 @@
+package history
+
+import (
+	"fmt"
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var UndoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Revert the most recent change",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := db.Undo()
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			fmt.Println("Nothing to undo.")
+			return nil
+		}
+		fmt.Printf("Undid: %s (%d row(s)).\n", c.Summary, len(c.Entries))
+		return nil
+	},
+}
@@ -0,0 +1,34 @@
+package networth
+
+import (
+	"fmt"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	addName string
+	addKind string
+)
+
+var AddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add an asset or liability",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := db.InsertHolding(db.Holding{Name: addName, Kind: addKind}); err != nil {
+			return err
+		}
+		fmt.Println("Holding added.")
+		return nil
+	},
+}
+
+func init() {
+	AddCmd.Flags().StringVarP(&addName, "name", "n", "", "Name, e.g. House (required)")
+	AddCmd.Flags().StringVarP(&addKind, "kind", "k", db.HoldingAsset, "asset or liability")
+	_ = AddCmd.MarkFlagRequired("name")
+
+	NetWorthCmd.AddCommand(AddCmd)
+}
@@ -0,0 +1,37 @@
+package networth
+
+import (
+	"fmt"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var deleteName string
+
+var DeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a holding and its valuations",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		h, err := db.GetHoldingByName(deleteName)
+		if err != nil {
+			return err
+		}
+		if h == nil {
+			return fmt.Errorf("holding %q not found", deleteName)
+		}
+		if err := db.DeleteHolding(h.ID); err != nil {
+			return err
+		}
+		fmt.Println("Holding deleted. Run \"undo\" to restore it.")
+		return nil
+	},
+}
+
+func init() {
+	DeleteCmd.Flags().StringVarP(&deleteName, "name", "n", "", "Holding name (required)")
+	_ = DeleteCmd.MarkFlagRequired("name")
+
+	NetWorthCmd.AddCommand(DeleteCmd)
+}
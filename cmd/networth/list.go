@@ -0,0 +1,38 @@
+package networth
+
+import (
+	"fmt"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List assets and liabilities with their latest valuation",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		holdings, err := db.GetHoldings()
+		if err != nil {
+			return err
+		}
+		if len(holdings) == 0 {
+			fmt.Println("No holdings found.")
+			return nil
+		}
+
+		fmt.Println("ID | Name | Kind | Latest value | As of")
+		for _, h := range holdings {
+			asOf := "-"
+			if !h.LatestDate.IsZero() {
+				asOf = h.LatestDate.Format("2006-01-02")
+			}
+			fmt.Printf("%d | %s | %s | %.2f | %s\n", h.ID, h.Name, h.Kind, h.LatestValue, asOf)
+		}
+		return nil
+	},
+}
+
+func init() {
+	NetWorthCmd.AddCommand(ListCmd)
+}
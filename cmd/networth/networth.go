@@ -0,0 +1,12 @@
+package networth
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var NetWorthCmd = &cobra.Command{
+	Use:   "networth",
+	Short: "Manage assets and liabilities valued by hand",
+	Long: "Track holdings that are not in the transactions table, such as a house, car, loan or pension, " +
+		"by recording dated valuations. \"report networth\" combines them with the transaction balance.",
+}
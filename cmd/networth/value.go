@@ -0,0 +1,52 @@
+package networth
+
+import (
+	"fmt"
+	"time"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	valueName   string
+	valueAmount float64
+	valueDate   string
+)
+
+var ValueCmd = &cobra.Command{
+	Use:   "value",
+	Short: "Record the value of an asset or the amount owed on a liability",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		h, err := db.GetHoldingByName(valueName)
+		if err != nil {
+			return err
+		}
+		if h == nil {
+			return fmt.Errorf("holding %q not found", valueName)
+		}
+
+		date := time.Now()
+		if valueDate != "" {
+			if date, err = time.Parse("2006-01-02", valueDate); err != nil {
+				return fmt.Errorf("invalid date format: %w", err)
+			}
+		}
+		if err := db.SetValuation(h.ID, date, valueAmount); err != nil {
+			return err
+		}
+		fmt.Println("Valuation recorded.")
+		return nil
+	},
+}
+
+func init() {
+	ValueCmd.Flags().StringVarP(&valueName, "name", "n", "", "Holding name (required)")
+	ValueCmd.Flags().Float64VarP(&valueAmount, "amount", "a", 0, "Value, or amount owed for liabilities (required)")
+	ValueCmd.Flags().StringVarP(&valueDate, "date", "", "", "Date YYYY-MM-DD (optional; defaults to today)")
+	_ = ValueCmd.MarkFlagRequired("name")
+	_ = ValueCmd.MarkFlagRequired("amount")
+
+	NetWorthCmd.AddCommand(ValueCmd)
+}
@@ -0,0 +1,63 @@
+package recurring
+
+import (
+	"fmt"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	addDescription string
+	addCategory    string
+	addAmount      float64
+	addFrequency   string
+	addDay         int
+	addStart       string
+	addEnd         string
+)
+
+var AddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a recurring transaction",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		start, err := parseDate("start", addStart)
+		if err != nil {
+			return err
+		}
+		end, err := parseDate("end", addEnd)
+		if err != nil {
+			return err
+		}
+
+		r := db.Recurring{
+			Description: addDescription,
+			Category:    addCategory,
+			Amount:      addAmount,
+			Frequency:   addFrequency,
+			Day:         addDay,
+			Start:       start,
+			End:         end,
+		}
+		if err := db.InsertRecurring(r); err != nil {
+			return err
+		}
+		fmt.Println("Recurring transaction added.")
+		return nil
+	},
+}
+
+func init() {
+	AddCmd.Flags().StringVarP(&addDescription, "description", "d", "", "Description (required)")
+	AddCmd.Flags().StringVarP(&addCategory, "category", "c", "Uncategorized", "Category")
+	AddCmd.Flags().Float64VarP(&addAmount, "amount", "a", 0, "Amount; negative for expenses (required)")
+	AddCmd.Flags().StringVarP(&addFrequency, "frequency", "f", db.FreqMonthly, "daily, weekly, monthly or yearly")
+	AddCmd.Flags().IntVar(&addDay, "day", 0, "Day of the month for monthly/yearly (defaults to the start day)")
+	AddCmd.Flags().StringVar(&addStart, "start", "", "First date YYYY-MM-DD (defaults to today)")
+	AddCmd.Flags().StringVar(&addEnd, "end", "", "Last date YYYY-MM-DD (optional)")
+	_ = AddCmd.MarkFlagRequired("description")
+	_ = AddCmd.MarkFlagRequired("amount")
+
+	RecurringCmd.AddCommand(AddCmd)
+}
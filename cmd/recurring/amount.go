@@ -0,0 +1,49 @@
+package recurring
+
+import (
+	"fmt"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	amountID    int
+	amountValue float64
+	amountFrom  string
+)
+
+var AmountCmd = &cobra.Command{
+	Use:   "amount",
+	Short: "Change the amount of a recurring transaction from a date on",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		r, err := db.GetRecurringByID(amountID)
+		if err != nil {
+			return err
+		}
+		if r == nil {
+			return fmt.Errorf("recurring transaction %d not found", amountID)
+		}
+		from, err := parseDate("from", amountFrom)
+		if err != nil {
+			return err
+		}
+		if err := db.SetRecurringAmount(r.ID, from, amountValue); err != nil {
+			return err
+		}
+		fmt.Println("Amount change scheduled.")
+		return nil
+	},
+}
+
+func init() {
+	AmountCmd.Flags().IntVar(&amountID, "id", 0, "Recurring transaction ID (required)")
+	AmountCmd.Flags().Float64VarP(&amountValue, "amount", "a", 0, "New amount (required)")
+	AmountCmd.Flags().StringVar(&amountFrom, "from", "", "First date the new amount applies, YYYY-MM-DD (required)")
+	_ = AmountCmd.MarkFlagRequired("id")
+	_ = AmountCmd.MarkFlagRequired("amount")
+	_ = AmountCmd.MarkFlagRequired("from")
+
+	RecurringCmd.AddCommand(AmountCmd)
+}
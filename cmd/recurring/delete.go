@@ -0,0 +1,30 @@
+package recurring
+
+import (
+	"fmt"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var deleteID int
+
+var DeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a recurring template (transactions already created are kept)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := db.DeleteRecurring(deleteID); err != nil {
+			return err
+		}
+		fmt.Println("Recurring transaction deleted. Run \"undo\" to restore it.")
+		return nil
+	},
+}
+
+func init() {
+	DeleteCmd.Flags().IntVar(&deleteID, "id", 0, "Recurring transaction ID (required)")
+	_ = DeleteCmd.MarkFlagRequired("id")
+
+	RecurringCmd.AddCommand(DeleteCmd)
+}
@@ -0,0 +1,64 @@
+package recurring
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recurring transactions",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list, err := db.GetRecurring()
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			fmt.Println("No recurring transactions found.")
+			return nil
+		}
+
+		now := time.Now()
+		fmt.Println("ID | Description | Category | Amount | Schedule | Start | End | Next | Status")
+		for _, r := range list {
+			end, next := "-", "-"
+			if !r.End.IsZero() {
+				end = r.End.Format("2006-01-02")
+			}
+			if dates := r.Occurrences(now, now.AddDate(1, 0, 1)); len(dates) > 0 {
+				next = dates[0].Format("2006-01-02")
+			}
+			status := "active"
+			if r.Paused {
+				status = "paused"
+			}
+			fmt.Printf("%d | %s | %s | %.2f | %s | %s | %s | %s | %s\n",
+				r.ID, r.Description, r.Category, r.AmountOn(now), schedule(r), r.Start.Format("2006-01-02"), end, next, status)
+			for _, c := range r.Changes {
+				fmt.Printf("    from %s: %.2f\n", c.From.Format("2006-01-02"), c.Amount)
+			}
+		}
+		return nil
+	},
+}
+
+func schedule(r db.Recurring) string {
+	switch r.Frequency {
+	case db.FreqMonthly:
+		return fmt.Sprintf("monthly on %d", r.Day)
+	case db.FreqYearly:
+		return fmt.Sprintf("yearly on %s %d", r.Start.Month().String()[:3], r.Day)
+	case db.FreqWeekly:
+		return "weekly on " + strings.ToLower(r.Start.Weekday().String()[:3])
+	}
+	return r.Frequency
+}
+
+func init() {
+	RecurringCmd.AddCommand(ListCmd)
+}
@@ -0,0 +1,48 @@
+package recurring
+
+import (
+	"fmt"
+	"time"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var pauseID int
+
+var PauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Stop creating transactions for a recurring template",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := db.SetRecurringPaused(pauseID, true, time.Now()); err != nil {
+			return err
+		}
+		fmt.Println("Recurring transaction paused.")
+		return nil
+	},
+}
+
+var resumeID int
+
+var ResumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resume a paused template; dates missed while paused are skipped",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := db.SetRecurringPaused(resumeID, false, time.Now()); err != nil {
+			return err
+		}
+		fmt.Println("Recurring transaction resumed.")
+		return nil
+	},
+}
+
+func init() {
+	PauseCmd.Flags().IntVar(&pauseID, "id", 0, "Recurring transaction ID (required)")
+	_ = PauseCmd.MarkFlagRequired("id")
+	ResumeCmd.Flags().IntVar(&resumeID, "id", 0, "Recurring transaction ID (required)")
+	_ = ResumeCmd.MarkFlagRequired("id")
+
+	RecurringCmd.AddCommand(PauseCmd)
+	RecurringCmd.AddCommand(ResumeCmd)
+}
@@ -0,0 +1,28 @@
+package recurring
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var RecurringCmd = &cobra.Command{
+	Use:   "recurring",
+	Short: "Manage recurring transactions such as rent, salary and subscriptions",
+	Long: "Recurring templates are turned into transactions by \"recurring run\". Each due date is created " +
+		"only once, so run can safely be called from cron or on every login.",
+}
+
+// parseDate parses an optional YYYY-MM-DD flag; an empty value gives the
+// zero time.
+func parseDate(flag, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return t, fmt.Errorf("invalid --%s date: %w", flag, err)
+	}
+	return t, nil
+}
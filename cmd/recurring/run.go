@@ -0,0 +1,38 @@
+package recurring
+
+import (
+	"fmt"
+	"time"
+
+	"personal-finance-cli/db"
+	"personal-finance-cli/internal/alert"
+
+	"github.com/spf13/cobra"
+)
+
+var RunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Create the transactions that are due from every active recurring template",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		created, err := db.RunRecurring(time.Now())
+		if err != nil {
+			return err
+		}
+		if len(created) == 0 {
+			fmt.Println("Nothing due.")
+			return nil
+		}
+
+		for _, t := range created {
+			fmt.Printf("Created %s | %s | %s | %.2f\n", t.Date.Format("2006-01-02"), t.Description, t.Category, t.Amount)
+		}
+		for _, w := range alert.Warnings(alert.Evaluate(alert.TouchesOf(created)...)) {
+			fmt.Println(w)
+		}
+		return nil
+	},
+}
+
+func init() {
+	RecurringCmd.AddCommand(RunCmd)
+}
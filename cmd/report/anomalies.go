@@ -0,0 +1,67 @@
+package report
+
+import (
+	"fmt"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	anomaliesFrom   string
+	anomaliesTo     string
+	anomaliesFormat string
+)
+
+var AnomaliesCmd = &cobra.Command{
+	Use:   "anomalies",
+	Short: "Flag unusually large charges, large first charges from new payees and same-day duplicates",
+	Long: "Compares every expense in the range with the full history of its payee and category. Imports " +
+		"from the TUI and \"watch\" run the same checks on the rows they insert.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateFormat(anomaliesFormat); err != nil {
+			return err
+		}
+		from, to, err := parseRange(anomaliesFrom, anomaliesTo)
+		if err != nil {
+			return err
+		}
+
+		anomalies, err := db.DetectAnomalies(from, to)
+		if err != nil {
+			return err
+		}
+
+		switch anomaliesFormat {
+		case formatJSON:
+			return writeJSON(anomalies)
+		case formatCSV:
+			records := [][]string{{"id", "date", "description", "category", "amount", "kind", "reason"}}
+			for _, a := range anomalies {
+				records = append(records, []string{fmt.Sprint(a.ID), a.Date.Format("2006-01-02"), a.Description,
+					a.Category, money(a.Amount), a.Kind, a.Reason})
+			}
+			return writeCSV(records)
+		}
+
+		if len(anomalies) == 0 {
+			fmt.Println("No anomalies found.")
+			return nil
+		}
+		fmt.Println("ID | Date | Description | Category | Amount | Flag | Why")
+		for _, a := range anomalies {
+			fmt.Printf("%d | %s | %s | %s | %.2f | %s%s%s | %s\n", a.ID, a.Date.Format("2006-01-02"), a.Description,
+				a.Category, a.Amount, colorYellow, a.Kind, colorReset, a.Reason)
+		}
+		return nil
+	},
+}
+
+func init() {
+	AnomaliesCmd.Flags().StringVar(&anomaliesFrom, "from", "", "Start date YYYY-MM-DD (defaults to start of this year)")
+	AnomaliesCmd.Flags().StringVar(&anomaliesTo, "to", "", "End date YYYY-MM-DD (defaults to today)")
+	AnomaliesCmd.Flags().StringVarP(&anomaliesFormat, "format", "f", formatTable, "Output format: table, json or csv")
+
+	ReportCmd.AddCommand(AnomaliesCmd)
+}
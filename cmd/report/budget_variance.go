@@ -0,0 +1,114 @@
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	colorReset  = "\033[0m"
+	colorYellow = "\033[33m"
+	colorRed    = "\033[31m"
+)
+
+var (
+	varianceFrom   string
+	varianceTo     string
+	varianceFormat string
+)
+
+var BudgetVarianceCmd = &cobra.Command{
+	Use:   "budget-variance",
+	Short: "Budgeted vs actual spend per category and month, with cumulative variance",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateFormat(varianceFormat); err != nil {
+			return err
+		}
+		from, to, err := parseMonthRange(varianceFrom, varianceTo, 6)
+		if err != nil {
+			return err
+		}
+
+		r, err := db.GetBudgetVariance(from, to)
+		if err != nil {
+			return err
+		}
+
+		switch varianceFormat {
+		case formatJSON:
+			return writeJSON(r)
+		case formatCSV:
+			records := [][]string{{"category", "month", "budgeted", "actual", "variance", "cumulative_variance", "tendency"}}
+			for _, c := range r.Categories {
+				for _, m := range c.Months {
+					records = append(records, []string{c.Category, m.Month, money(m.Budgeted), money(m.Actual),
+						money(m.Variance), money(m.Cumulative), c.Tendency})
+				}
+			}
+			return writeCSV(records)
+		}
+
+		if len(r.Categories) == 0 {
+			fmt.Printf("No expense budgets between %s and %s.\n", r.From, r.To)
+			return nil
+		}
+
+		fmt.Printf("Budget variance %s to %s (negative variance = over budget)\n", r.From, r.To)
+		for _, c := range r.Categories {
+			fmt.Printf("\n%s", c.Category)
+			switch c.Tendency {
+			case db.TendencyOver:
+				fmt.Printf(" %s(%s)%s", colorRed, c.Tendency, colorReset)
+			case db.TendencyUnder:
+				fmt.Printf(" %s(%s)%s", colorYellow, c.Tendency, colorReset)
+			}
+			fmt.Println()
+			fmt.Println("  Month | Budgeted | Actual | Variance | Cumulative")
+			for _, m := range c.Months {
+				variance := money(m.Variance)
+				if m.OverBudget {
+					variance = colorRed + variance + colorReset
+				}
+				fmt.Printf("  %s | %.2f | %.2f | %s | %.2f\n",
+					m.Month, m.Budgeted, m.Actual, variance, m.Cumulative)
+			}
+		}
+		return nil
+	},
+}
+
+// parseMonthRange reads --from/--to months (YYYY-MM), defaulting to the
+// last defaultMonths months including the current one.
+func parseMonthRange(fromStr, toStr string, defaultMonths int) (time.Time, time.Time, error) {
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, -(defaultMonths - 1), 0)
+
+	var err error
+	if fromStr != "" {
+		if from, err = time.Parse("2006-01", fromStr); err != nil {
+			return from, to, fmt.Errorf("invalid --from month, expected YYYY-MM")
+		}
+	}
+	if toStr != "" {
+		if to, err = time.Parse("2006-01", toStr); err != nil {
+			return from, to, fmt.Errorf("invalid --to month, expected YYYY-MM")
+		}
+	}
+	if to.Before(from) {
+		return from, to, fmt.Errorf("--to is before --from")
+	}
+	return from, to, nil
+}
+
+func init() {
+	BudgetVarianceCmd.Flags().StringVar(&varianceFrom, "from", "", "First month YYYY-MM (defaults to 5 months ago)")
+	BudgetVarianceCmd.Flags().StringVar(&varianceTo, "to", "", "Last month YYYY-MM (defaults to this month)")
+	BudgetVarianceCmd.Flags().StringVarP(&varianceFormat, "format", "f", formatTable, "Output format: table, json or csv")
+
+	ReportCmd.AddCommand(BudgetVarianceCmd)
+}
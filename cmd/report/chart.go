@@ -0,0 +1,40 @@
+package report
+
+import (
+	"math"
+	"strings"
+)
+
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders values as a single line of block characters scaled
+// between their minimum and maximum.
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+
+	var sb strings.Builder
+	for _, v := range values {
+		idx := 0
+		if hi > lo {
+			idx = int((v - lo) / (hi - lo) * float64(len(sparkTicks)-1))
+		}
+		sb.WriteRune(sparkTicks[idx])
+	}
+	return sb.String()
+}
+
+// bar renders v as a horizontal bar of up to width cells relative to max.
+func bar(v, max float64, width int) string {
+	if max <= 0 || v <= 0 {
+		return ""
+	}
+	n := int(math.Round(v / max * float64(width)))
+	return strings.Repeat("█", n)
+}
@@ -0,0 +1,98 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	forecastMonths int
+	forecastFormat string
+)
+
+var ForecastCmd = &cobra.Command{
+	Use:   "forecast",
+	Short: "Project the balance day by day from recurring transactions and remaining budgets",
+	Long: "Starts from the running balance of all transactions and applies, for every future day, the " +
+		"recurring templates, any monthly charges detected in the last six months that no template covers, " +
+		"and an even share of what is left of each expense budget in its period. Days where the balance " +
+		"would go negative are flagged.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateFormat(forecastFormat); err != nil {
+			return err
+		}
+
+		fc, err := db.GetForecast(forecastMonths, time.Now())
+		if err != nil {
+			return err
+		}
+
+		switch forecastFormat {
+		case formatJSON:
+			return writeJSON(fc)
+		case formatCSV:
+			records := [][]string{{"date", "inflow", "outflow", "balance", "negative", "items"}}
+			for _, d := range fc.Days {
+				records = append(records, []string{d.Date, money(d.Inflow), money(d.Outflow), money(d.Balance),
+					fmt.Sprint(d.Negative), strings.Join(d.Items, "; ")})
+			}
+			return writeCSV(records)
+		}
+
+		if len(fc.Recurring) == 0 {
+			fmt.Println("No recurring transactions declared or detected.")
+		} else {
+			fmt.Println("Recurring: Schedule | Description | Category | Amount | Source")
+			for _, r := range fc.Recurring {
+				fmt.Printf("  %s | %s | %s | %.2f | %s\n", schedule(r.Frequency, r.Day), r.Description, r.Category, r.Amount, r.Source)
+			}
+		}
+		if len(fc.Budgets) > 0 {
+			fmt.Printf("Budget allowances: %s\n", strings.Join(fc.Budgets, ", "))
+		}
+
+		values := make([]float64, len(fc.Days))
+		max := 0.0
+		for i, d := range fc.Days {
+			values[i] = d.Balance
+			max = math.Max(max, d.Balance)
+		}
+		fmt.Printf("\nForecast %s to %s  %s\n", fc.From, fc.To, sparkline(values))
+		fmt.Println("Date | In | Out | Balance | Items | Chart")
+		for _, d := range fc.Days {
+			balance := money(d.Balance)
+			if d.Negative {
+				balance = colorRed + balance + " NEGATIVE" + colorReset
+			}
+			fmt.Printf("%s | %.2f | %.2f | %s | %s | %s\n",
+				d.Date, d.Inflow, d.Outflow, balance, strings.Join(d.Items, ", "), bar(d.Balance, max, 30))
+		}
+
+		fmt.Printf("\nStart %.2f | End %.2f | Lowest %.2f\n", fc.StartBalance, fc.EndBalance, fc.LowestBalance)
+		if fc.FirstNegative != "" {
+			fmt.Printf("%sBalance goes negative on %s%s\n", colorRed, fc.FirstNegative, colorReset)
+		}
+		return nil
+	},
+}
+
+// schedule describes when a recurring item falls due, e.g. "monthly on 3".
+func schedule(frequency string, day int) string {
+	if frequency == db.FreqMonthly || frequency == db.FreqYearly {
+		return fmt.Sprintf("%s on %d", frequency, day)
+	}
+	return frequency
+}
+
+func init() {
+	ForecastCmd.Flags().IntVarP(&forecastMonths, "months", "m", 3, "Number of months to project")
+	ForecastCmd.Flags().StringVarP(&forecastFormat, "format", "f", formatTable, "Output format: table, json or csv")
+
+	ReportCmd.AddCommand(ForecastCmd)
+}
@@ -0,0 +1,129 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	networthFrom     string
+	networthTo       string
+	networthInterval string
+	networthFormat   string
+)
+
+var NetWorthCmd = &cobra.Command{
+	Use:   "networth",
+	Short: "Net worth over time from the transaction balance and asset/liability valuations",
+	Long: "Cash is the running balance of all transactions; assets and liabilities use their latest " +
+		"valuation recorded with \"networth value\" on or before each date.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateFormat(networthFormat); err != nil {
+			return err
+		}
+		from, to, err := parseRange(networthFrom, networthTo)
+		if err != nil {
+			return err
+		}
+		dates, err := intervalEnds(from, to, networthInterval)
+		if err != nil {
+			return err
+		}
+
+		points, err := db.GetNetWorthSeries(dates)
+		if err != nil {
+			return err
+		}
+
+		switch networthFormat {
+		case formatJSON:
+			return writeJSON(points)
+		case formatCSV:
+			names := holdingNames(points)
+			header := append([]string{"date", "cash"}, names...)
+			header = append(header, "assets", "liabilities", "net_worth")
+			records := [][]string{header}
+			for _, p := range points {
+				rec := []string{p.Date, money(p.Cash)}
+				for _, n := range names {
+					rec = append(rec, money(p.Holdings[n]))
+				}
+				rec = append(rec, money(p.Assets), money(p.Liabilities), money(p.NetWorth))
+				records = append(records, rec)
+			}
+			return writeCSV(records)
+		}
+
+		values := make([]float64, len(points))
+		max := 0.0
+		for i, p := range points {
+			values[i] = p.NetWorth
+			if p.NetWorth > max {
+				max = p.NetWorth
+			}
+		}
+		fmt.Printf("Net worth %s to %s  %s\n", from.Format("2006-01-02"), to.Format("2006-01-02"), sparkline(values))
+		fmt.Println("Date | Cash | Assets | Liabilities | Net worth | Chart")
+		for _, p := range points {
+			fmt.Printf("%s | %.2f | %.2f | %.2f | %.2f | %s\n",
+				p.Date, p.Cash, p.Assets, p.Liabilities, p.NetWorth, bar(p.NetWorth, max, 30))
+		}
+		return nil
+	},
+}
+
+// intervalEnds returns the last day of every week, month or year between
+// from and to, with to itself as the final point.
+func intervalEnds(from, to time.Time, interval string) ([]time.Time, error) {
+	var step func(time.Time) time.Time
+	var first time.Time
+	switch interval {
+	case db.GroupWeek:
+		offset := (7 - int(from.Weekday())) % 7
+		first = from.AddDate(0, 0, offset)
+		step = func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+	case db.GroupMonth:
+		first = time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, -1)
+		step = func(t time.Time) time.Time { return time.Date(t.Year(), t.Month()+2, 0, 0, 0, 0, 0, time.UTC) }
+	case db.GroupYear:
+		first = time.Date(from.Year(), 12, 31, 0, 0, 0, 0, time.UTC)
+		step = func(t time.Time) time.Time { return t.AddDate(1, 0, 0) }
+	default:
+		return nil, fmt.Errorf("invalid interval %q: use week, month or year", interval)
+	}
+
+	var dates []time.Time
+	for d := first; d.Before(to); d = step(d) {
+		dates = append(dates, d)
+	}
+	return append(dates, to), nil
+}
+
+func holdingNames(points []db.NetWorthPoint) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, p := range points {
+		for n := range p.Holdings {
+			if !seen[n] {
+				seen[n] = true
+				names = append(names, n)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	NetWorthCmd.Flags().StringVar(&networthFrom, "from", "", "Start date YYYY-MM-DD (defaults to start of this year)")
+	NetWorthCmd.Flags().StringVar(&networthTo, "to", "", "End date YYYY-MM-DD (defaults to today)")
+	NetWorthCmd.Flags().StringVarP(&networthInterval, "interval", "i", db.GroupMonth, "Interval: week, month or year")
+	NetWorthCmd.Flags().StringVarP(&networthFormat, "format", "f", formatTable, "Output format: table, json or csv")
+
+	ReportCmd.AddCommand(NetWorthCmd)
+}
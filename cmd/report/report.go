@@ -0,0 +1,72 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var ReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Reports over transactions and budgets",
+}
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+func validateFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, formatCSV:
+		return nil
+	}
+	return fmt.Errorf("unknown format %q (use table, json or csv)", format)
+}
+
+func writeJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeCSV(records [][]string) error {
+	w := csv.NewWriter(os.Stdout)
+	if err := w.WriteAll(records); err != nil {
+		return err
+	}
+	return w.Error()
+}
+
+// parseRange reads --from/--to dates, defaulting to the start of the current
+// year and today.
+func parseRange(fromStr, toStr string) (time.Time, time.Time, error) {
+	now := time.Now()
+	from := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var err error
+	if fromStr != "" {
+		if from, err = time.Parse("2006-01-02", fromStr); err != nil {
+			return from, to, fmt.Errorf("invalid --from date: %w", err)
+		}
+	}
+	if toStr != "" {
+		if to, err = time.Parse("2006-01-02", toStr); err != nil {
+			return from, to, fmt.Errorf("invalid --to date: %w", err)
+		}
+	}
+	if to.Before(from) {
+		return from, to, fmt.Errorf("--to is before --from")
+	}
+	return from, to, nil
+}
+
+func money(v float64) string {
+	return fmt.Sprintf("%.2f", v)
+}
@@ -0,0 +1,137 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	subscriptionsAll     bool
+	subscriptionsPromote string
+	subscriptionsFormat  string
+)
+
+var SubscriptionsCmd = &cobra.Command{
+	Use:   "subscriptions",
+	Short: "Detect subscriptions and other charges that recur at regular intervals",
+	Long: "Groups expenses by payee (the description without digits and punctuation) and lists those " +
+		"charged weekly, monthly or yearly at a steady price, with the next expected charge, annualized cost " +
+		"and price changes. Use --promote with (part of) a payee to turn a detection into a recurring template.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateFormat(subscriptionsFormat); err != nil {
+			return err
+		}
+
+		subs, err := db.DetectSubscriptions(time.Now())
+		if err != nil {
+			return err
+		}
+
+		if subscriptionsPromote != "" {
+			return promoteSubscription(subs, subscriptionsPromote)
+		}
+
+		if !subscriptionsAll {
+			active := subs[:0]
+			for _, s := range subs {
+				if !s.Lapsed {
+					active = append(active, s)
+				}
+			}
+			subs = active
+		}
+
+		switch subscriptionsFormat {
+		case formatJSON:
+			return writeJSON(subs)
+		case formatCSV:
+			records := [][]string{{"payee", "category", "cadence", "charges", "last_charge", "last_amount",
+				"next_expected", "annualized", "price_changes", "lapsed", "tracked"}}
+			for _, s := range subs {
+				records = append(records, []string{s.Payee, s.Category, s.Frequency, fmt.Sprint(s.Charges),
+					s.Last.Format("2006-01-02"), money(s.LastAmount), s.NextExpected.Format("2006-01-02"),
+					money(s.Annualized), priceChanges(s.PriceChanges), fmt.Sprint(s.Lapsed), fmt.Sprint(s.Tracked)})
+			}
+			return writeCSV(records)
+		}
+
+		if len(subs) == 0 {
+			fmt.Println("No subscriptions detected.")
+			return nil
+		}
+		total := 0.0
+		fmt.Println("Payee | Category | Cadence | Charges | Last charge | Amount | Next expected | Per year | Price changes | Status")
+		for _, s := range subs {
+			status := "active"
+			switch {
+			case s.Lapsed:
+				status = "lapsed"
+			case s.Tracked:
+				status = "tracked"
+			}
+			if !s.Lapsed {
+				total += s.Annualized
+			}
+			fmt.Printf("%s | %s | %s | %d | %s | %.2f | %s | %.2f | %s | %s\n",
+				s.Description, s.Category, s.Frequency, s.Charges, s.Last.Format("2006-01-02"), s.LastAmount,
+				s.NextExpected.Format("2006-01-02"), s.Annualized, priceChanges(s.PriceChanges), status)
+		}
+		fmt.Printf("Total per year: %.2f\n", total)
+		return nil
+	},
+}
+
+// promoteSubscription turns the one detection whose payee contains name into
+// a recurring template.
+func promoteSubscription(subs []db.Subscription, name string) error {
+	var matches []db.Subscription
+	for _, s := range subs {
+		if strings.Contains(s.Payee, strings.ToLower(name)) || strings.EqualFold(s.Description, name) {
+			matches = append(matches, s)
+		}
+	}
+	switch {
+	case len(matches) == 0:
+		return fmt.Errorf("no subscription detected for %q", name)
+	case len(matches) > 1:
+		names := make([]string, len(matches))
+		for i, s := range matches {
+			names[i] = s.Description
+		}
+		return fmt.Errorf("%q matches several subscriptions: %s", name, strings.Join(names, ", "))
+	}
+
+	s := matches[0]
+	if s.Tracked {
+		return fmt.Errorf("%q already has a recurring template", s.Description)
+	}
+	if err := db.PromoteSubscription(s); err != nil {
+		return err
+	}
+	fmt.Printf("Recurring template added for %s, starting %s.\n", s.Description, s.NextExpected.Format("2006-01-02"))
+	return nil
+}
+
+func priceChanges(changes []db.PriceChange) string {
+	if len(changes) == 0 {
+		return "-"
+	}
+	parts := make([]string, len(changes))
+	for i, c := range changes {
+		parts[i] = fmt.Sprintf("%.2f→%.2f on %s", c.From, c.To, c.Date.Format("2006-01-02"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func init() {
+	SubscriptionsCmd.Flags().BoolVar(&subscriptionsAll, "all", false, "Include lapsed subscriptions")
+	SubscriptionsCmd.Flags().StringVar(&subscriptionsPromote, "promote", "", "Create a recurring template for the subscription whose payee contains this text")
+	SubscriptionsCmd.Flags().StringVarP(&subscriptionsFormat, "format", "f", formatTable, "Output format: table, json or csv")
+
+	ReportCmd.AddCommand(SubscriptionsCmd)
+}
@@ -0,0 +1,74 @@
+package report
+
+import (
+	"fmt"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	summaryFrom    string
+	summaryTo      string
+	summaryGroupBy string
+	summaryFormat  string
+)
+
+var SummaryCmd = &cobra.Command{
+	Use:   "summary",
+	Short: "Income, expenses and net per period with a category breakdown",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateFormat(summaryFormat); err != nil {
+			return err
+		}
+		from, to, err := parseRange(summaryFrom, summaryTo)
+		if err != nil {
+			return err
+		}
+
+		s, err := db.GetSummary(from, to, summaryGroupBy)
+		if err != nil {
+			return err
+		}
+
+		switch summaryFormat {
+		case formatJSON:
+			return writeJSON(s)
+		case formatCSV:
+			records := [][]string{{"period", "category", "income", "expenses", "net", "share_pct"}}
+			for _, p := range s.Periods {
+				records = append(records, []string{p.Period, "TOTAL", money(p.Income), money(p.Expenses), money(p.Net), "100.0"})
+				for _, c := range p.Categories {
+					records = append(records, []string{p.Period, c.Category, money(c.Income), money(c.Expenses), money(c.Net), fmt.Sprintf("%.1f", c.Share)})
+				}
+			}
+			return writeCSV(records)
+		}
+
+		if len(s.Periods) == 0 {
+			fmt.Printf("No transactions between %s and %s.\n", s.From, s.To)
+			return nil
+		}
+
+		fmt.Printf("Summary %s to %s by %s\n", s.From, s.To, s.GroupBy)
+		for _, p := range s.Periods {
+			fmt.Printf("\n%s | Income %.2f | Expenses %.2f | Net %.2f\n", p.Period, p.Income, p.Expenses, p.Net)
+			fmt.Println("  Category | Income | Expenses | Net | Share")
+			for _, c := range p.Categories {
+				fmt.Printf("  %s | %.2f | %.2f | %.2f | %.1f%%\n", c.Category, c.Income, c.Expenses, c.Net, c.Share)
+			}
+		}
+		fmt.Printf("\nTotal | Income %.2f | Expenses %.2f | Net %.2f\n", s.Income, s.Expenses, s.Net)
+		return nil
+	},
+}
+
+func init() {
+	SummaryCmd.Flags().StringVar(&summaryFrom, "from", "", "Start date YYYY-MM-DD (defaults to start of this year)")
+	SummaryCmd.Flags().StringVar(&summaryTo, "to", "", "End date YYYY-MM-DD, inclusive (defaults to today)")
+	SummaryCmd.Flags().StringVarP(&summaryGroupBy, "group-by", "g", db.GroupMonth, "Group by week, month or year")
+	SummaryCmd.Flags().StringVarP(&summaryFormat, "format", "f", formatTable, "Output format: table, json or csv")
+
+	ReportCmd.AddCommand(SummaryCmd)
+}
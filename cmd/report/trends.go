@@ -0,0 +1,92 @@
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	trendsCategory string
+	trendsMonths   int
+	trendsWindow   int
+	trendsFormat   string
+)
+
+var TrendsCmd = &cobra.Command{
+	Use:   "trends",
+	Short: "Monthly spending of a category with month-over-month and year-over-year changes",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateFormat(trendsFormat); err != nil {
+			return err
+		}
+		if trendsWindow < 1 {
+			return fmt.Errorf("--window must be at least 1")
+		}
+
+		points, err := db.GetCategoryTrend(trendsCategory, trendsMonths, trendsWindow, time.Now())
+		if err != nil {
+			return err
+		}
+
+		switch trendsFormat {
+		case formatJSON:
+			return writeJSON(points)
+		case formatCSV:
+			records := [][]string{{"month", "amount", "mom", "mom_pct", "yoy", "yoy_pct", "rolling_avg"}}
+			for _, p := range points {
+				records = append(records, []string{p.Month, money(p.Amount), optMoney(p.MoM), optPct(p.MoMPct),
+					optMoney(p.YoY), optPct(p.YoYPct), money(p.RollingAvg)})
+			}
+			return writeCSV(records)
+		}
+
+		name := trendsCategory
+		if name == "" {
+			name = "All categories"
+		}
+		values := make([]float64, len(points))
+		max := 0.0
+		for i, p := range points {
+			values[i] = p.Amount
+			if p.Amount > max {
+				max = p.Amount
+			}
+		}
+
+		fmt.Printf("%s spending, last %d months  %s\n", name, trendsMonths, sparkline(values))
+		fmt.Printf("Month | Amount | MoM | MoM %% | YoY | YoY %% | %d-mo avg | Chart\n", trendsWindow)
+		for _, p := range points {
+			fmt.Printf("%s | %.2f | %s | %s | %s | %s | %.2f | %s\n",
+				p.Month, p.Amount, optMoney(p.MoM), optPct(p.MoMPct), optMoney(p.YoY), optPct(p.YoYPct),
+				p.RollingAvg, bar(p.Amount, max, 30))
+		}
+		return nil
+	},
+}
+
+func optMoney(v *float64) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%+.2f", *v)
+}
+
+func optPct(v *float64) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f%%", *v)
+}
+
+func init() {
+	TrendsCmd.Flags().StringVarP(&trendsCategory, "category", "c", "", "Category (defaults to all spending)")
+	TrendsCmd.Flags().IntVarP(&trendsMonths, "months", "m", 12, "Number of months to show")
+	TrendsCmd.Flags().IntVarP(&trendsWindow, "window", "w", 3, "Months in the rolling average")
+	TrendsCmd.Flags().StringVarP(&trendsFormat, "format", "f", formatTable, "Output format: table, json or csv")
+
+	ReportCmd.AddCommand(TrendsCmd)
+}
@@ -3,7 +3,16 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"personal-finance-cli/cmd/alert"
+	"personal-finance-cli/cmd/budget"
+	"personal-finance-cli/cmd/envelope"
+	"personal-finance-cli/cmd/history"
+	"personal-finance-cli/cmd/networth"
+	"personal-finance-cli/cmd/recurring"
+	"personal-finance-cli/cmd/report"
 	"personal-finance-cli/cmd/transaction"
+	"personal-finance-cli/cmd/trash"
+	"personal-finance-cli/cmd/watch"
 	"personal-finance-cli/db"
 
 	"github.com/spf13/cobra"
@@ -25,6 +34,17 @@ func init() {
 	cobra.OnInitialize(initDatabase)
 
 	RootCmd.AddCommand(transaction.TransactionCmd)
+	RootCmd.AddCommand(budget.BudgetCmd)
+	RootCmd.AddCommand(envelope.EnvelopeCmd)
+	RootCmd.AddCommand(alert.AlertCmd)
+	RootCmd.AddCommand(report.ReportCmd)
+	RootCmd.AddCommand(networth.NetWorthCmd)
+	RootCmd.AddCommand(recurring.RecurringCmd)
+	RootCmd.AddCommand(watch.WatchCmd)
+	RootCmd.AddCommand(history.HistoryCmd)
+	RootCmd.AddCommand(history.UndoCmd)
+	RootCmd.AddCommand(history.RedoCmd)
+	RootCmd.AddCommand(trash.TrashCmd)
 }
 
 func initDatabase() {
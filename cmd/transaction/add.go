@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"personal-finance-cli/db"
+	"personal-finance-cli/internal/alert"
 
 	"github.com/spf13/cobra"
 )
@@ -14,6 +15,8 @@ var (
 	addDescription string
 	addCategory    string
 	addDate        string
+	addAccount     string
+	addTags        string
 )
 
 // AddCmd represents the "transaction add" command
@@ -37,6 +40,8 @@ var AddCmd = &cobra.Command{
 			Description: addDescription,
 			Category:    addCategory,
 			Date:        txDate,
+			Account:     addAccount,
+			Tags:        db.ParseTags(addTags),
 		}
 
 		if err := db.InsertTransaction(tx); err != nil {
@@ -44,6 +49,9 @@ var AddCmd = &cobra.Command{
 		}
 
 		fmt.Println("Transaction added.")
+		for _, w := range alert.Warnings(alert.Evaluate(alert.Touch{Category: tx.Category, Date: tx.Date})) {
+			fmt.Println(w)
+		}
 		return nil
 	},
 }
@@ -53,6 +61,8 @@ func init() {
 	AddCmd.Flags().StringVarP(&addDescription, "description", "d", "", "Description")
 	AddCmd.Flags().StringVarP(&addCategory, "category", "c", "Uncategorized", "Category")
 	AddCmd.Flags().StringVarP(&addDate, "date", "", "", "Date YYYY-MM-DD (optional; defaults to today)")
+	AddCmd.Flags().StringVar(&addAccount, "account", "", "Account the money moved through (optional)")
+	AddCmd.Flags().StringVar(&addTags, "tags", "", "Comma-separated tags (optional)")
 
 	_ = AddCmd.MarkFlagRequired("amount")
 
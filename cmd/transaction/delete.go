@@ -16,7 +16,7 @@ var DeleteCmd = &cobra.Command{
 		if err := db.DeleteTransaction(deleteID); err != nil {
 			return err
 		}
-		fmt.Println("Transaction deleted.")
+		fmt.Printf("Transaction moved to the trash. Run \"trash restore --id %d\" or \"undo\" to bring it back.\n", deleteID)
 		return nil
 	},
 }
@@ -3,6 +3,7 @@ package transaction
 import (
 	"fmt"
 	"personal-finance-cli/db"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -38,10 +39,10 @@ var ListCmd = &cobra.Command{
 			return nil
 		}
 
-		fmt.Println("ID | Amount | Category | Date | Description")
+		fmt.Println("ID | Amount | Category | Date | Description | Account | Tags")
 		for _, t := range txs {
-			fmt.Printf("%d | %.2f | %s | %s | %s\n",
-				t.ID, t.Amount, t.Category, t.Date.Format("2006-01-02"), t.Description)
+			fmt.Printf("%d | %.2f | %s | %s | %s | %s | %s\n",
+				t.ID, t.Amount, t.Category, t.Date.Format("2006-01-02"), t.Description, t.Account, strings.Join(t.Tags, ","))
 		}
 		return nil
 	},
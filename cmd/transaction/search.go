@@ -0,0 +1,43 @@
+package transaction
+
+import (
+	"fmt"
+	"strings"
+
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var searchLimit int
+
+var SearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search transaction descriptions, categories and tags",
+	Long: "Finds transactions matching every word of the query (as a prefix) in their description, category or tags, " +
+		"best matches first, with the matched words highlighted.",
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		results, err := db.SearchTransactions(strings.Join(args, " "), searchLimit)
+		if err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			fmt.Println("No transactions found.")
+			return nil
+		}
+
+		highlight := strings.NewReplacer(db.HighlightStart, "\033[1;33m", db.HighlightEnd, "\033[0m")
+		fmt.Println("ID | Amount | Category | Date | Description | Tags")
+		for _, r := range results {
+			fmt.Printf("%d | %.2f | %s | %s | %s | %s\n",
+				r.ID, r.Amount, r.Category, r.Date.Format("2006-01-02"), highlight.Replace(r.Highlighted), strings.Join(r.Tags, ","))
+		}
+		return nil
+	},
+}
+
+func init() {
+	SearchCmd.Flags().IntVarP(&searchLimit, "limit", "l", 50, "Maximum number of results")
+	TransactionCmd.AddCommand(SearchCmd)
+}
@@ -3,6 +3,7 @@ package transaction
 import (
 	"fmt"
 	"personal-finance-cli/db"
+	"personal-finance-cli/internal/alert"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -41,6 +42,9 @@ var UpdateCmd = &cobra.Command{
 			return err
 		}
 		fmt.Println("Transaction updated.")
+		for _, w := range alert.Warnings(alert.Evaluate(alert.Touch{Category: tx.Category, Date: tx.Date})) {
+			fmt.Println(w)
+		}
 		return nil
 	},
 }
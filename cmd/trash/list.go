@@ -0,0 +1,33 @@
+package trash
+
+import (
+	"fmt"
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List everything in the trash, most recently deleted first",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		items, err := db.GetTrash()
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			fmt.Println("The trash is empty.")
+			return nil
+		}
+
+		fmt.Println("Type | ID | Deleted | Item")
+		for _, it := range items {
+			fmt.Printf("%s | %d | %s | %s\n", it.Kind, it.ID, it.DeletedAt.Local().Format("2006-01-02 15:04"), it.Summary)
+		}
+		return nil
+	},
+}
+
+func init() {
+	TrashCmd.AddCommand(ListCmd)
+}
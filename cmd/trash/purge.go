@@ -0,0 +1,60 @@
+package trash
+
+import (
+	"fmt"
+	"personal-finance-cli/db"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	purgeID        int
+	purgeType      string
+	purgeAll       bool
+	purgeOlderThan int
+)
+
+var PurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Permanently remove items from the trash",
+	Long: "Purge one item with --id, everything deleted more than --older-than days ago, or the whole trash with " +
+		"--all. Purged items cannot be restored or undone.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch {
+		case purgeID > 0:
+			if err := db.PurgeFromTrash(purgeType, purgeID); err != nil {
+				return err
+			}
+			fmt.Printf("Purged %s %d.\n", purgeType, purgeID)
+			return nil
+		case purgeAll:
+			n, err := db.PurgeTrash(time.Time{})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Purged %d item(s).\n", n)
+			return nil
+		case cmd.Flags().Changed("older-than"):
+			if purgeOlderThan < 0 {
+				return fmt.Errorf("--older-than must be zero or more days")
+			}
+			n, err := db.PurgeTrash(time.Now().AddDate(0, 0, -purgeOlderThan))
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Purged %d item(s) deleted more than %d day(s) ago.\n", n, purgeOlderThan)
+			return nil
+		}
+		return fmt.Errorf("give --id, --older-than or --all")
+	},
+}
+
+func init() {
+	PurgeCmd.Flags().IntVarP(&purgeID, "id", "i", 0, "ID of the item to purge")
+	PurgeCmd.Flags().StringVarP(&purgeType, "type", "t", db.TrashTransaction, typeFlagUsage)
+	PurgeCmd.Flags().BoolVar(&purgeAll, "all", false, "Empty the whole trash")
+	PurgeCmd.Flags().IntVar(&purgeOlderThan, "older-than", 0, "Purge items deleted more than this many days ago")
+
+	TrashCmd.AddCommand(PurgeCmd)
+}
@@ -0,0 +1,33 @@
+package trash
+
+import (
+	"fmt"
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	restoreID   int
+	restoreType string
+)
+
+var RestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Bring a deleted transaction or budget back",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := db.RestoreFromTrash(restoreType, restoreID); err != nil {
+			return err
+		}
+		fmt.Printf("Restored %s %d.\n", restoreType, restoreID)
+		return nil
+	},
+}
+
+func init() {
+	RestoreCmd.Flags().IntVarP(&restoreID, "id", "i", 0, "ID of the item to restore (required)")
+	RestoreCmd.Flags().StringVarP(&restoreType, "type", "t", db.TrashTransaction, typeFlagUsage)
+	_ = RestoreCmd.MarkFlagRequired("id")
+
+	TrashCmd.AddCommand(RestoreCmd)
+}
@@ -0,0 +1,40 @@
+package trash
+
+import (
+	"fmt"
+	"personal-finance-cli/db"
+
+	"github.com/spf13/cobra"
+)
+
+var retentionDays int
+
+var RetentionCmd = &cobra.Command{
+	Use:   "retention",
+	Short: "Show or set how many days deleted items stay in the trash",
+	Long: "Items older than the retention period are purged automatically the next time the program starts. " +
+		"A retention of 0 keeps them until they are purged by hand.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cmd.Flags().Changed("days") {
+			if err := db.SetTrashRetentionDays(retentionDays); err != nil {
+				return err
+			}
+		}
+		days, err := db.TrashRetentionDays()
+		if err != nil {
+			return err
+		}
+		if days == 0 {
+			fmt.Println("Deleted items are kept until purged by hand.")
+			return nil
+		}
+		fmt.Printf("Deleted items are purged after %d day(s).\n", days)
+		return nil
+	},
+}
+
+func init() {
+	RetentionCmd.Flags().IntVarP(&retentionDays, "days", "d", db.DefaultTrashRetentionDays, "Days to keep deleted items (0 keeps them forever)")
+
+	TrashCmd.AddCommand(RetentionCmd)
+}
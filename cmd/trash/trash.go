@@ -0,0 +1,18 @@
+package trash
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const typeFlagUsage = "Item type: transaction or budget"
+
+var TrashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "List, restore and purge deleted transactions and budgets",
+	Long: "Deleted transactions and budgets are kept in the trash, hidden from every listing and report, until they " +
+		"are purged by hand or automatically once they are older than the retention period.",
+}
+
+func init() {
+	// child commands attach here (list, restore, purge, retention)
+}
@@ -2,222 +2,314 @@ package budget
 
 import (
 	"fmt"
+	"personal-finance-cli/cmd/tui/transaction"
+	"personal-finance-cli/cmd/tui/ui"
 	"personal-finance-cli/db"
+	"personal-finance-cli/internal/period"
 	"strconv"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
-func RunTUI() {
-	app := tview.NewApplication()
-
-	title := tview.NewTextView().
-		SetTextAlign(tview.AlignCenter).
-		SetText("[::b][green]💰 Budgets Menu[::-]").
-		SetDynamicColors(true)
-
-	labels := []string{"List Budgets", "Add Budget", "Back"}
+func RunTUI(u *ui.UI) {
+	labels := []string{"List Budgets", "Add Budget", "Variance Report", "Back"}
 	actions := []func(){
-		showBudgets,
-		AddInteractive,
-		func() { app.Stop() },
+		func() { showBudgets(u) },
+		func() { AddInteractive(u) },
+		func() { showVariance(u) },
+		u.Pop,
 	}
+	u.Push(ui.Menu("💰 Budgets Menu", labels, actions), nil)
+}
 
-	current := 0
-	buttonFlex := tview.NewFlex().SetDirection(tview.FlexRow)
-	buttons := []*tview.Button{}
+// ------------------ Budget Table -------------------
 
-	for i, label := range labels {
-		idx := i
-		btn := tview.NewButton("[green]" + label).SetSelectedFunc(actions[idx])
-		btn.SetBorder(true)
-		buttons = append(buttons, btn)
-		buttonFlex.AddItem(btn, 3, 0, false)
-	}
+func showBudgets(u *ui.UI) {
+	table := tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	table.SetBorder(true).SetTitle("[green]Budgets (Enter=Transactions, e=Edit/Delete, ESC=Back)").SetTitleAlign(tview.AlignCenter)
+	summary := tview.NewTextView().SetDynamicColors(true).SetTextAlign(tview.AlignCenter)
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(summary, 1, 0, false)
+
+	var budgets []db.Budget
+	var occurrences []db.BudgetOccurrence
+	load := func() {
+		var err error
+		budgets, err = db.GetBudgets()
+		if err != nil {
+			u.Error("Error fetching budgets: %v", err)
+			return
+		}
 
-	highlight := func() {
-		for i, btn := range buttons {
-			if i == current {
-				btn.SetLabel("[white][green]" + labels[i] + "[::-]")
-			} else {
-				btn.SetLabel("[green]" + labels[i] + "[::-]")
-			}
+		row, _ := table.GetSelection()
+		table.Clear()
+		headers := []string{"ID", "Kind", "Category", "Period", "Amount", "Spent", "Remaining", "Progress"}
+		for i, h := range headers {
+			table.SetCell(0, i, tview.NewTableCell(fmt.Sprintf("[::b][green]%s[::-]", h)).SetSelectable(false))
 		}
-	}
 
-	highlight()
+		now := time.Now()
+		occurrences = make([]db.BudgetOccurrence, len(budgets))
+		for r, b := range budgets {
+			table.SetCell(r+1, 0, tview.NewTableCell(strconv.Itoa(b.ID)))
+			table.SetCell(r+1, 1, tview.NewTableCell(b.Kind))
+			table.SetCell(r+1, 2, tview.NewTableCell(tview.Escape(b.Category)))
+			table.SetCell(r+1, 3, tview.NewTableCell(b.Period))
+			table.SetCell(r+1, 4, tview.NewTableCell(fmt.Sprintf("%.2f", b.Amount)).SetAlign(tview.AlignRight))
 
-	layout := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(title, 5, 1, false).
-		AddItem(buttonFlex, 0, 2, true)
-
-	layout.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		switch event.Key() {
-		case tcell.KeyUp:
-			current--
-			if current < 0 {
-				current = len(buttons) - 1
+			occ, err := db.GetBudgetOccurrence(b, now)
+			if err != nil {
+				u.Error("Error computing budget %d: %v", b.ID, err)
+				return
 			}
-			highlight()
-			return nil
-		case tcell.KeyDown:
-			current++
-			if current >= len(buttons) {
-				current = 0
+			occurrences[r] = occ
+			pct := 0.0
+			if occ.Available > 0 {
+				pct = occ.Actual / occ.Available * 100
 			}
-			highlight()
-			return nil
-		case tcell.KeyEnter:
-			actions[current]()
-			return nil
+			left := fmt.Sprintf("%.2f", occ.Balance)
+			if occ.Balance < 0 && b.Kind == db.KindExpense {
+				left = "[red]" + left
+			}
+			table.SetCell(r+1, 5, tview.NewTableCell(fmt.Sprintf("%.2f", occ.Actual)).SetAlign(tview.AlignRight))
+			table.SetCell(r+1, 6, tview.NewTableCell(left).SetAlign(tview.AlignRight))
+			table.SetCell(r+1, 7, tview.NewTableCell(fmt.Sprintf("%s %3.0f%%", ui.ProgressBar(b.Kind, pct, 20), pct)).
+				SetExpansion(1))
 		}
-		return event
-	})
-
-	if err := app.SetRoot(layout, true).EnableMouse(true).Run(); err != nil {
-		fmt.Println(err)
-	}
-}
-
-// ------------------ Budget Table -------------------
-
-func showBudgets() {
-	budgets, err := db.GetBudgets()
-	if err != nil {
-		fmt.Println("Error fetching budgets:", err)
-		return
-	}
-
-	app := tview.NewApplication()
-	table := tview.NewTable().SetSelectable(true, false)
-	table.SetBorder(true).SetTitle("[green]Budgets (Enter=Edit/Delete, ESC=Back)").SetTitleAlign(tview.AlignCenter)
-
-	headers := []string{"ID", "Category", "Amount", "Period"}
-	for i, h := range headers {
-		table.SetCell(0, i, tview.NewTableCell(fmt.Sprintf("[::b][green]%s[::-]", h)).SetSelectable(false))
-	}
+		if row < 1 {
+			row = 1
+		}
+		if row > len(budgets) {
+			row = len(budgets)
+		}
+		table.Select(row, 0)
 
-	for r, b := range budgets {
-		table.SetCell(r+1, 0, tview.NewTableCell(strconv.Itoa(b.ID)))
-		table.SetCell(r+1, 1, tview.NewTableCell(b.Category))
-		table.SetCell(r+1, 2, tview.NewTableCell(fmt.Sprintf("%.2f", b.Amount)))
-		table.SetCell(r+1, 3, tview.NewTableCell(b.Period))
+		start, end := period.MonthOf(time.Now()).Range(time.Now())
+		income, expenses, err := db.GetPeriodTotals(start, end)
+		if err != nil {
+			u.Error("Error fetching totals: %v", err)
+			return
+		}
+		summary.SetText(fmt.Sprintf("[green]This month: income %.2f | expenses %.2f | savings rate %.1f%%",
+			income, expenses, db.SavingsRate(income, expenses)*100))
 	}
+	load()
 
 	table.SetSelectedFunc(func(row, column int) {
-		if row == 0 {
+		if row == 0 || row > len(budgets) || occurrences[row-1].From == "" {
 			return
 		}
-		b := budgets[row-1]
-		showBudgetActions(b, table, app)
+		showBudgetTransactions(u, budgets[row-1], occurrences[row-1])
+	})
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && event.Rune() == 'e' {
+			if row, _ := table.GetSelection(); row > 0 && row <= len(budgets) {
+				showBudgetActions(u, budgets[row-1])
+			}
+			return nil
+		}
+		return event
 	})
 
 	table.SetDoneFunc(func(key tcell.Key) {
 		if key == tcell.KeyEscape {
-			app.Stop()
+			u.Pop()
 		}
 	})
 
-	if err := app.SetRoot(table, true).EnableMouse(true).Run(); err != nil {
-		fmt.Println(err)
-	}
+	u.Push(layout, load)
 }
 
-func showBudgetActions(b db.Budget, parentTable *tview.Table, app *tview.Application) {
+func showBudgetActions(u *ui.UI, b db.Budget) {
 	modal := tview.NewModal().
 		SetText(fmt.Sprintf("[green]Budget ID %d\nChoose an action[::-]", b.ID)).
 		AddButtons([]string{"Edit", "Delete", "Cancel"}).
 		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			u.Pop()
 			switch buttonLabel {
 			case "Edit":
-				app.Suspend(func() { UpdateInteractive(b) })
+				UpdateInteractive(u, b)
 			case "Delete":
-				app.Suspend(func() {
-					if err := db.DeleteBudget(b.ID); err != nil {
-						fmt.Println("Delete error:", err)
-					} else {
-						fmt.Println("Budget deleted!")
-					}
-				})
-			case "Cancel":
+				if err := db.DeleteBudget(b.ID); err != nil {
+					u.Error("Delete error: %v", err)
+					return
+				}
+				u.Changed()
+				u.Info("Budget %d moved to the trash.", b.ID)
 			}
-			app.SetRoot(parentTable, true)
 		})
 
-	app.SetRoot(modal, false)
+	u.Modal(modal)
 }
 
-// ------------------ Add / Update Forms -------------------
+// showBudgetTransactions drills into what b measures in its current period:
+// the spending or income in its category, or every transaction for a savings
+// goal. Recategorizing from there moves rows out of the budget.
+func showBudgetTransactions(u *ui.UI, b db.Budget, occ db.BudgetOccurrence) {
+	from, _ := time.Parse("2006-01-02", occ.From)
+	to, _ := time.Parse("2006-01-02", occ.To)
+	f := db.TransactionFilter{From: from, To: to, CategoryExact: true}
+	switch b.Kind {
+	case db.KindExpense:
+		f.Category, f.Flow = b.Category, db.FlowExpenses
+	case db.KindIncome:
+		f.Category, f.Flow = b.Category, db.FlowIncome
+	}
+	transaction.ShowFiltered(u, fmt.Sprintf("%s budget, %s to %s", b.Category, occ.From, occ.To), f)
+}
 
-func AddInteractive() {
-	app := tview.NewApplication()
-	var form *tview.Form
-	form = tview.NewForm().
-		AddInputField("Category", "", 20, nil, nil).
-		AddInputField("Amount", "", 20, nil, nil).
-		AddInputField("Period", "monthly", 20, nil, nil).
-		AddButton("Save", func() {
-			category := form.GetFormItemByLabel("Category").(*tview.InputField).GetText()
-			amountText := form.GetFormItemByLabel("Amount").(*tview.InputField).GetText()
-			period := form.GetFormItemByLabel("Period").(*tview.InputField).GetText()
+// ------------------ Variance Report -------------------
 
-			amount, err := strconv.ParseFloat(amountText, 64)
-			if err != nil {
-				fmt.Println("Invalid amount")
-				return
-			}
+func showVariance(u *ui.UI) {
+	table := tview.NewTable().SetSelectable(true, false).SetFixed(1, 1)
+	table.SetBorder(true).SetTitleAlign(tview.AlignCenter)
 
-			b := db.Budget{
-				Category: category,
-				Amount:   amount,
-				Period:   period,
-			}
+	load := func() {
+		now := time.Now()
+		r, err := db.GetBudgetVariance(now.AddDate(0, -5, 0), now)
+		if err != nil {
+			u.Error("Error building variance report: %v", err)
+			return
+		}
+
+		table.Clear()
+		table.SetTitle(fmt.Sprintf("[green]Budget Variance %s to %s (red = over budget, ESC=Back)", r.From, r.To))
+		headers := []string{"Category", "Month", "Budgeted", "Actual", "Variance", "Cumulative"}
+		for i, h := range headers {
+			table.SetCell(0, i, tview.NewTableCell(fmt.Sprintf("[::b][green]%s[::-]", h)).SetSelectable(false))
+		}
 
-			if err := db.InsertBudget(b); err != nil {
-				fmt.Println("Error saving budget:", err)
-			} else {
-				fmt.Println("Budget added!")
+		row := 1
+		for _, c := range r.Categories {
+			label := tview.Escape(c.Category)
+			switch c.Tendency {
+			case db.TendencyOver:
+				label = fmt.Sprintf("[red]%s (%s)", label, c.Tendency)
+			case db.TendencyUnder:
+				label = fmt.Sprintf("[yellow]%s (%s)", label, c.Tendency)
 			}
-			app.Stop()
-		}).
-		AddButton("Cancel", func() { app.Stop() })
+			for i, m := range c.Months {
+				if i == 0 {
+					table.SetCell(row, 0, tview.NewTableCell(label))
+				} else {
+					table.SetCell(row, 0, tview.NewTableCell(""))
+				}
+				variance := fmt.Sprintf("%.2f", m.Variance)
+				if m.OverBudget {
+					variance = "[red]" + variance
+				}
+				table.SetCell(row, 1, tview.NewTableCell(m.Month))
+				table.SetCell(row, 2, tview.NewTableCell(fmt.Sprintf("%.2f", m.Budgeted)))
+				table.SetCell(row, 3, tview.NewTableCell(fmt.Sprintf("%.2f", m.Actual)))
+				table.SetCell(row, 4, tview.NewTableCell(variance))
+				table.SetCell(row, 5, tview.NewTableCell(fmt.Sprintf("%.2f", m.Cumulative)))
+				row++
+			}
+		}
+	}
+	load()
+
+	table.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			u.Pop()
+		}
+	})
+
+	u.Push(table, load)
+}
 
-	form.SetBorder(true).SetTitle("[green]Add Budget").SetTitleAlign(tview.AlignLeft)
-	app.SetRoot(form, true).EnableMouse(true).Run()
+// ------------------ Add / Update Forms -------------------
+
+func AddInteractive(u *ui.UI) {
+	b := db.Budget{Period: "monthly", Thresholds: db.DefaultThresholds}
+	showBudgetForm(u, b, "[green]Add Budget", func(b db.Budget) error {
+		if err := db.InsertBudget(b); err != nil {
+			return err
+		}
+		u.Info("Budget added.")
+		return nil
+	})
 }
 
-func UpdateInteractive(b db.Budget) {
-	app := tview.NewApplication()
+func UpdateInteractive(u *ui.UI, b db.Budget) {
+	showBudgetForm(u, b, fmt.Sprintf("[green]Edit Budget ID %d", b.ID), func(b db.Budget) error {
+		if err := db.UpdateBudget(b); err != nil {
+			return err
+		}
+		u.Info("Budget %d updated.", b.ID)
+		return nil
+	})
+}
+
+// showBudgetForm edits a copy of b and hands it to save; the form stays open
+// with the error in the status bar if validation or saving fails.
+func showBudgetForm(u *ui.UI, b db.Budget, title string, save func(db.Budget) error) {
+	amount := ""
+	if b.ID != 0 {
+		amount = fmt.Sprintf("%.2f", b.Amount)
+	}
+
 	var form *tview.Form
 	form = tview.NewForm().
 		AddInputField("Category", b.Category, 20, nil, nil).
-		AddInputField("Amount", fmt.Sprintf("%.2f", b.Amount), 20, nil, nil).
+		AddInputField("Amount", amount, 20, nil, nil).
+		AddDropDown("Kind", db.BudgetKinds, optionIndex(db.BudgetKinds, b.Kind), nil).
 		AddInputField("Period", b.Period, 20, nil, nil).
+		AddDropDown("Rollover", db.RolloverModes, optionIndex(db.RolloverModes, b.Rollover), nil).
+		AddInputField("Alerts (%)", db.FormatThresholds(b.Thresholds), 20, nil, nil).
 		AddButton("Save", func() {
 			category := form.GetFormItemByLabel("Category").(*tview.InputField).GetText()
 			amountText := form.GetFormItemByLabel("Amount").(*tview.InputField).GetText()
-			period := form.GetFormItemByLabel("Period").(*tview.InputField).GetText()
+			periodText := form.GetFormItemByLabel("Period").(*tview.InputField).GetText()
+			_, kind := form.GetFormItemByLabel("Kind").(*tview.DropDown).GetCurrentOption()
+			_, rollover := form.GetFormItemByLabel("Rollover").(*tview.DropDown).GetCurrentOption()
 
 			amount, err := strconv.ParseFloat(amountText, 64)
 			if err != nil {
-				fmt.Println("Invalid amount")
+				u.Error("Invalid amount")
+				return
+			}
+
+			if _, err := period.Parse(periodText); err != nil {
+				u.Error("Invalid period: %v", err)
+				return
+			}
+
+			thresholds, err := db.ParseThresholds(form.GetFormItemByLabel("Alerts (%)").(*tview.InputField).GetText())
+			if err != nil {
+				u.Error("Invalid alerts: %v", err)
 				return
 			}
 
 			b.Category = category
 			b.Amount = amount
-			b.Period = period
+			b.Period = periodText
+			b.Kind = kind
+			b.Rollover = rollover
+			b.Thresholds = thresholds
 
-			if err := db.UpdateBudget(b); err != nil {
-				fmt.Println("Error updating budget:", err)
-			} else {
-				fmt.Println("Budget updated!")
+			if err := save(b); err != nil {
+				u.Error("Error saving budget: %v", err)
+				return
 			}
-			app.Stop()
+			u.Pop()
+			u.Changed()
 		}).
-		AddButton("Cancel", func() { app.Stop() })
+		AddButton("Cancel", u.Pop)
+
+	form.SetCancelFunc(u.Pop)
+	form.SetBorder(true).SetTitle(title).SetTitleAlign(tview.AlignLeft)
+	u.Push(form, nil)
+}
 
-	form.SetBorder(true).SetTitle(fmt.Sprintf("[green]Edit Budget ID %d", b.ID)).SetTitleAlign(tview.AlignLeft)
-	app.SetRoot(form, true).EnableMouse(true).Run()
+func optionIndex(options []string, value string) int {
+	for i, o := range options {
+		if o == value {
+			return i
+		}
+	}
+	return 0
 }
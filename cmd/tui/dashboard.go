@@ -0,0 +1,257 @@
+package tui
+
+import (
+	"fmt"
+	"personal-finance-cli/cmd/tui/budget"
+	"personal-finance-cli/cmd/tui/envelope"
+	"personal-finance-cli/cmd/tui/transaction"
+	"personal-finance-cli/cmd/tui/trash"
+	"personal-finance-cli/cmd/tui/ui"
+	"personal-finance-cli/db"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	topCategories      = 5
+	recentTransactions = 10
+	// dashboardRefresh picks up changes made outside the TUI, such as
+	// imports by "watch" or commands run in another terminal.
+	dashboardRefresh = 30 * time.Second
+)
+
+// showDashboard is the home screen: this month's totals, top spending
+// categories, budget progress, balances and the latest transactions. It
+// refreshes after every change made in the TUI and on a timer.
+func showDashboard(u *ui.UI) {
+	header := tview.NewTextView().SetDynamicColors(true).SetTextAlign(tview.AlignCenter)
+	month := panel("This Month")
+	balances := panel("Balances")
+	categories := panel("Top Spending")
+	budgets := panel("Budgets")
+	recent := tview.NewTable().SetFixed(1, 0)
+	recent.SetBorder(true).SetTitle("[green]Last Transactions").SetTitleAlign(tview.AlignLeft)
+	keys := tview.NewTextView().SetDynamicColors(true).SetTextAlign(tview.AlignCenter).
+		SetText("[green]t[-] Transactions  [green]a[-] Add  [green]i[-] Import  [green]b[-] Budgets  [green]e[-] Envelopes  [green]x[-] Trash  [green]^Z[-]/[green]^Y[-] Undo/Redo  [green]q[-] Quit")
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(header, 1, 0, false).
+		AddItem(tview.NewFlex().
+			AddItem(month, 0, 1, false).
+			AddItem(balances, 0, 1, false), 7, 0, false).
+		AddItem(tview.NewFlex().
+			AddItem(categories, 0, 1, false).
+			AddItem(budgets, 0, 1, false), 0, 1, false).
+		AddItem(recent, recentTransactions+3, 0, true).
+		AddItem(keys, 1, 0, false)
+
+	load := func() {
+		now := time.Now()
+		header.SetText(fmt.Sprintf("[::b][green]💰 Personal Finance — %s[::-]", now.Format("January 2006")))
+
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		summary, err := db.GetSummary(start, now, db.GroupMonth)
+		if err != nil {
+			u.Error("Error loading dashboard: %v", err)
+			return
+		}
+		month.SetText(monthText(summary))
+		categories.SetText(categoriesText(summary))
+
+		text, err := budgetsText(now)
+		if err != nil {
+			u.Error("Error loading budgets: %v", err)
+			return
+		}
+		budgets.SetText(text)
+
+		text, err = balancesText(now)
+		if err != nil {
+			u.Error("Error loading balances: %v", err)
+			return
+		}
+		balances.SetText(text)
+
+		txs, err := db.GetRecentTransactions(recentTransactions)
+		if err != nil {
+			u.Error("Error fetching transactions: %v", err)
+			return
+		}
+		fillRecent(recent, txs)
+	}
+	load()
+
+	layout.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			u.App.Stop()
+			return nil
+		}
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+		switch event.Rune() {
+		case 't':
+			transaction.RunTUI(u)
+		case 'a':
+			transaction.AddInteractive(u)
+		case 'i':
+			transaction.ImportInteractive(u)
+		case 'b':
+			budget.RunTUI(u)
+		case 'e':
+			envelope.RunTUI(u)
+		case 'x':
+			trash.RunTUI(u)
+		case 'q':
+			u.App.Stop()
+		default:
+			return event
+		}
+		return nil
+	})
+
+	// Only reload while the dashboard is on top, so a hidden dashboard never
+	// reports errors over another screen.
+	go func() {
+		for range time.Tick(dashboardRefresh) {
+			u.App.QueueUpdateDraw(func() {
+				if u.Visible(layout) {
+					load()
+				}
+			})
+		}
+	}()
+
+	u.Push(layout, load)
+}
+
+func panel(title string) *tview.TextView {
+	tv := tview.NewTextView().SetDynamicColors(true)
+	tv.SetBorder(true).SetTitle("[green]" + title).SetTitleAlign(tview.AlignLeft)
+	return tv
+}
+
+func monthText(s db.Summary) string {
+	netColor := "green"
+	if s.Net < 0 {
+		netColor = "red"
+	}
+	return fmt.Sprintf(" Income    %12.2f\n Expenses  %12.2f\n Net       [%s]%12.2f[-]\n Savings   %11.1f%%",
+		s.Income, s.Expenses, netColor, s.Net, db.SavingsRate(s.Income, s.Expenses)*100)
+}
+
+func categoriesText(s db.Summary) string {
+	if len(s.Periods) == 0 {
+		return " No spending this month."
+	}
+	var lines []string
+	cats := s.Periods[0].Categories
+	for _, c := range cats {
+		if c.Expenses <= 0 || len(lines) == topCategories {
+			break
+		}
+		width := int(c.Share / 100 * 20)
+		lines = append(lines, fmt.Sprintf(" %-14s %10.2f [yellow]%s[-]",
+			tview.Escape(truncate(c.Category, 14)), c.Expenses, strings.Repeat("█", width)))
+	}
+	if len(lines) == 0 {
+		return " No spending this month."
+	}
+	return strings.Join(lines, "\n")
+}
+
+// budgetsText shows each budget's progress in the occurrence containing now:
+// what was spent or earned against what is available, rollover included, and
+// the balance left.
+func budgetsText(now time.Time) (string, error) {
+	budgets, err := db.GetBudgets()
+	if err != nil {
+		return "", err
+	}
+	if len(budgets) == 0 {
+		return " No budgets yet.", nil
+	}
+	var lines []string
+	for _, b := range budgets {
+		occ, err := db.GetBudgetOccurrence(b, now)
+		if err != nil {
+			return "", err
+		}
+		pct := 0.0
+		if occ.Available > 0 {
+			pct = occ.Actual / occ.Available * 100
+		}
+		lines = append(lines, fmt.Sprintf(" %-14s %s %4.0f%% %10.2f left",
+			tview.Escape(truncate(b.Category, 14)), ui.ProgressBar(b.Kind, pct, 15), pct, occ.Balance))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// balancesText lists the balance of each account (the running sum of its
+// transactions) and the latest valuation of each net worth holding.
+func balancesText(now time.Time) (string, error) {
+	points, err := db.GetNetWorthSeries([]time.Time{now})
+	if err != nil {
+		return "", err
+	}
+	p := points[0]
+
+	accounts, err := db.GetAccountBalances(now)
+	if err != nil {
+		return "", err
+	}
+	var lines []string
+	for _, a := range accounts {
+		name := a.Account
+		if name == "" {
+			name = "No account"
+		}
+		lines = append(lines, fmt.Sprintf(" %-16s %12.2f", tview.Escape(truncate(name, 16)), a.Balance))
+	}
+
+	holdings, err := db.GetHoldings()
+	if err != nil {
+		return "", err
+	}
+	for _, h := range holdings {
+		v, ok := p.Holdings[h.Name]
+		if !ok {
+			continue
+		}
+		if h.Kind == db.HoldingLiability {
+			v = -v
+		}
+		lines = append(lines, fmt.Sprintf(" %-16s %12.2f", tview.Escape(truncate(h.Name, 16)), v))
+	}
+	lines = append(lines, fmt.Sprintf(" [::b]%-16s %12.2f[::-]", "Net worth", p.NetWorth))
+	return strings.Join(lines, "\n"), nil
+}
+
+func fillRecent(table *tview.Table, txs []db.Transaction) {
+	table.Clear()
+	headers := []string{"Date", "Description", "Category", "Amount"}
+	for i, h := range headers {
+		table.SetCell(0, i, tview.NewTableCell(fmt.Sprintf("[::b][green]%s[::-]", h)))
+	}
+	for r, t := range txs {
+		amount := fmt.Sprintf("%.2f", t.Amount)
+		if t.Amount < 0 {
+			amount = "[red]" + amount
+		}
+		table.SetCell(r+1, 0, tview.NewTableCell(t.Date.Format("2006-01-02")))
+		table.SetCell(r+1, 1, tview.NewTableCell(tview.Escape(t.Description)).SetExpansion(1))
+		table.SetCell(r+1, 2, tview.NewTableCell(tview.Escape(t.Category)))
+		table.SetCell(r+1, 3, tview.NewTableCell(amount).SetAlign(tview.AlignRight))
+	}
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,219 @@
+package envelope
+
+import (
+	"fmt"
+	"personal-finance-cli/cmd/tui/ui"
+	"personal-finance-cli/db"
+	"strconv"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func RunTUI(u *ui.UI) {
+	month := time.Now().Format("2006-01")
+
+	if _, err := db.EnvelopeStart(); err != nil {
+		modal := tview.NewModal().
+			SetText(fmt.Sprintf("[green]Envelope mode is off.\nStart assigning income to envelopes from %s?[::-]", month)).
+			AddButtons([]string{"Enable", "Cancel"}).
+			SetDoneFunc(func(i int, lbl string) {
+				u.Pop()
+				if lbl != "Enable" {
+					return
+				}
+				if err := db.EnableEnvelopes(month); err != nil {
+					u.Error("Error enabling envelopes: %v", err)
+					return
+				}
+				u.Info("Envelope mode enabled from %s.", month)
+				showEnvelopes(u, month)
+			})
+		u.Modal(modal)
+		return
+	}
+
+	showEnvelopes(u, month)
+}
+
+// ------------------ Envelope Table -------------------
+
+func showEnvelopes(u *ui.UI, month string) {
+	header := tview.NewTextView().SetDynamicColors(true).SetTextAlign(tview.AlignCenter)
+	table := tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	table.SetBorder(true).SetTitle("[green]Envelopes (Enter=Actions, a=Assign new, ESC=Back)").SetTitleAlign(tview.AlignCenter)
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(header, 1, 0, false).
+		AddItem(table, 0, 1, true)
+
+	var envelopes []db.Envelope
+	load := func() {
+		ready, err := db.ReadyToAssign(month)
+		if err != nil {
+			u.Error("Error fetching envelopes: %v", err)
+			return
+		}
+		envelopes, err = db.GetEnvelopes(month)
+		if err != nil {
+			u.Error("Error fetching envelopes: %v", err)
+			return
+		}
+
+		readyColor := "green"
+		if ready < 0 {
+			readyColor = "red"
+		}
+		header.SetText(fmt.Sprintf("[::b][%s]Ready to assign (%s): %.2f[::-]", readyColor, month, ready))
+
+		row, _ := table.GetSelection()
+		table.Clear()
+		headers := []string{"Category", "Assigned", "Spent", "Balance"}
+		for i, h := range headers {
+			table.SetCell(0, i, tview.NewTableCell(fmt.Sprintf("[::b][green]%s[::-]", h)).SetSelectable(false))
+		}
+
+		for r, e := range envelopes {
+			balance := fmt.Sprintf("%.2f", e.Balance)
+			if e.Overspent() {
+				balance = "[red]" + balance + " (overspent)"
+			}
+			table.SetCell(r+1, 0, tview.NewTableCell(tview.Escape(e.Category)))
+			table.SetCell(r+1, 1, tview.NewTableCell(fmt.Sprintf("%.2f", e.Assigned)))
+			table.SetCell(r+1, 2, tview.NewTableCell(fmt.Sprintf("%.2f", e.Spent)))
+			table.SetCell(r+1, 3, tview.NewTableCell(balance))
+		}
+		if row < 1 {
+			row = 1
+		}
+		if row > len(envelopes) {
+			row = len(envelopes)
+		}
+		table.Select(row, 0)
+	}
+	load()
+
+	table.SetSelectedFunc(func(row, column int) {
+		if row == 0 || row > len(envelopes) {
+			return
+		}
+		showEnvelopeActions(u, month, envelopes[row-1])
+	})
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && event.Rune() == 'a' {
+			showAssignForm(u, month, "")
+			return nil
+		}
+		return event
+	})
+	table.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			u.Pop()
+		}
+	})
+
+	u.Push(layout, load)
+}
+
+func showEnvelopeActions(u *ui.UI, month string, e db.Envelope) {
+	buttons := []string{"Assign", "Move", "Cancel"}
+	if e.Overspent() {
+		buttons = []string{"Cover", "Assign", "Cancel"}
+	}
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("[green]Envelope %s\nBalance %.2f\nChoose an action[::-]", tview.Escape(e.Category), e.Balance)).
+		AddButtons(buttons).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			u.Pop()
+			switch buttonLabel {
+			case "Assign":
+				showAssignForm(u, month, e.Category)
+			case "Move":
+				showMoveForm(u, month, e.Category)
+			case "Cover":
+				showCoverForm(u, month, e.Category)
+			}
+		})
+	u.Modal(modal)
+}
+
+// ------------------ Assign / Move / Cover Forms -------------------
+
+// pushForm opens form; a successful save closes it and refreshes the
+// envelope table underneath.
+func pushForm(u *ui.UI, form *tview.Form, title string) {
+	form.SetCancelFunc(u.Pop)
+	form.SetBorder(true).SetTitle(title).SetTitleAlign(tview.AlignLeft)
+	u.Push(form, nil)
+}
+
+func saved(u *ui.UI, format string, args ...any) {
+	u.Pop()
+	u.Changed()
+	u.Info(format, args...)
+}
+
+func showAssignForm(u *ui.UI, month, category string) {
+	var form *tview.Form
+	form = tview.NewForm().
+		AddInputField("Category", category, 20, nil, nil).
+		AddInputField("Amount", "", 20, nil, nil).
+		AddButton("Save", func() {
+			cat := form.GetFormItemByLabel("Category").(*tview.InputField).GetText()
+			amount, err := strconv.ParseFloat(form.GetFormItemByLabel("Amount").(*tview.InputField).GetText(), 64)
+			if err != nil {
+				u.Error("Invalid amount")
+				return
+			}
+			if err := db.AssignToEnvelope(cat, month, amount); err != nil {
+				u.Error("%v", err)
+				return
+			}
+			saved(u, "Assigned %.2f to %s.", amount, cat)
+		}).
+		AddButton("Cancel", u.Pop)
+
+	pushForm(u, form, "[green]Assign to Envelope (negative returns to pool)")
+}
+
+func showMoveForm(u *ui.UI, month, from string) {
+	var form *tview.Form
+	form = tview.NewForm().
+		AddInputField("From", from, 20, nil, nil).
+		AddInputField("To", "", 20, nil, nil).
+		AddInputField("Amount", "", 20, nil, nil).
+		AddButton("Move", func() {
+			src := form.GetFormItemByLabel("From").(*tview.InputField).GetText()
+			dst := form.GetFormItemByLabel("To").(*tview.InputField).GetText()
+			amount, err := strconv.ParseFloat(form.GetFormItemByLabel("Amount").(*tview.InputField).GetText(), 64)
+			if err != nil {
+				u.Error("Invalid amount")
+				return
+			}
+			if err := db.MoveBetweenEnvelopes(src, dst, month, amount); err != nil {
+				u.Error("%v", err)
+				return
+			}
+			saved(u, "Moved %.2f from %s to %s.", amount, src, dst)
+		}).
+		AddButton("Cancel", u.Pop)
+
+	pushForm(u, form, "[green]Move Between Envelopes")
+}
+
+func showCoverForm(u *ui.UI, month, category string) {
+	var form *tview.Form
+	form = tview.NewForm().
+		AddInputField("Cover from", "", 20, nil, nil).
+		AddButton("Cover", func() {
+			from := form.GetFormItemByLabel("Cover from").(*tview.InputField).GetText()
+			if _, err := db.CoverOverspending(category, from, month); err != nil {
+				u.Error("%v", err)
+				return
+			}
+			saved(u, "Covered %s from %s.", category, from)
+		}).
+		AddButton("Cancel", u.Pop)
+
+	pushForm(u, form, fmt.Sprintf("[green]Cover Overspent %s", tview.Escape(category)))
+}
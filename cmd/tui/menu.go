@@ -1,79 +1,44 @@
 package tui
 
 import (
-	"personal-finance-cli/cmd/tui/budget"
-	"personal-finance-cli/cmd/tui/transaction"
+	"personal-finance-cli/cmd/tui/ui"
+	"personal-finance-cli/db"
 
 	"github.com/gdamore/tcell/v2"
-	"github.com/rivo/tview"
 )
 
+// RunMainMenu runs the whole TUI as one application; every screen is a page
+// on top of the dashboard. Ctrl+Z and Ctrl+Y undo and redo from any screen.
 func RunMainMenu() error {
-	app := tview.NewApplication()
-
-	title := tview.NewTextView().
-		SetTextAlign(tview.AlignCenter).
-		SetText("[::b][green]💰 Personal Finance CLI[::-]").
-		SetDynamicColors(true)
-
-	labels := []string{"Transactions", "Budgets", "Exit"}
-	actions := []func(){
-		func() { app.Suspend(func() { transaction.RunTUI() }) },
-		func() { app.Suspend(func() { budget.RunTUI() }) },
-		func() { app.Stop() },
-	}
-
-	current := 0
-	buttonFlex := tview.NewFlex().SetDirection(tview.FlexRow)
-	buttons := []*tview.Button{}
-
-	for i, label := range labels {
-		idx := i
-		btn := tview.NewButton("[green]" + label).
-			SetSelectedFunc(actions[idx])
-		btn.SetBorder(true)
-		buttons = append(buttons, btn)
-		buttonFlex.AddItem(btn, 3, 0, false)
-	}
-
-	highlight := func() {
-		for i, btn := range buttons {
-			if i == current {
-				btn.SetLabel("[white][green]" + labels[i] + "[::-]")
-			} else {
-				btn.SetLabel("[green]" + labels[i] + "[::-]")
-			}
-		}
-	}
-
-	highlight()
-
-	layout := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(title, 5, 1, false).
-		AddItem(buttonFlex, 0, 2, true)
-
-	layout.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+	db.SetOrigin(db.OriginTUI)
+	u := ui.New()
+	u.App.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		switch event.Key() {
-		case tcell.KeyUp:
-			current--
-			if current < 0 {
-				current = len(buttons) - 1
-			}
-			highlight()
-			return nil
-		case tcell.KeyDown:
-			current++
-			if current >= len(buttons) {
-				current = 0
-			}
-			highlight()
-			return nil
-		case tcell.KeyEnter:
-			actions[current]()
-			return nil
+		case tcell.KeyCtrlZ:
+			replay(u, "undo", "Undid", db.Undo)
+		case tcell.KeyCtrlY:
+			replay(u, "redo", "Redid", db.Redo)
+		default:
+			return event
 		}
-		return event
+		return nil
 	})
 
-	return app.SetRoot(layout, true).EnableMouse(true).Run()
+	showDashboard(u)
+	u.Info("Press a highlighted key to open a menu; ESC goes back.")
+	return u.Run()
+}
+
+// replay runs db.Undo or db.Redo and refreshes every open screen.
+func replay(u *ui.UI, verb, done string, fn func() (*db.Change, error)) {
+	c, err := fn()
+	switch {
+	case err != nil:
+		u.Error("Cannot %s: %v", verb, err)
+	case c == nil:
+		u.Info("Nothing to %s.", verb)
+	default:
+		u.Changed()
+		u.Info("%s: %s (%d row(s)).", done, c.Summary, len(c.Entries))
+	}
 }
@@ -0,0 +1,133 @@
+package transaction
+
+import (
+	"fmt"
+	"personal-finance-cli/cmd/tui/ui"
+	"personal-finance-cli/db"
+	"personal-finance-cli/internal/alert"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// ------------------ Bulk Actions -------------------
+
+// showBulkActions offers the actions that apply to every selected
+// transaction; done runs after one of them succeeds.
+func showBulkActions(u *ui.UI, ids []int, done func()) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("[green]%d transaction(s) selected\nChoose an action[::-]", len(ids))).
+		AddButtons([]string{"Set Category", "Add Tag", "Remove Tag", "Set Account", "Mark as Transfer", "Delete", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			u.Pop()
+			switch buttonLabel {
+			case "Set Category":
+				showBulkCategoryForm(u, ids, done)
+			case "Add Tag":
+				showBulkTextForm(u, ids, "Tag", "Add Tag to", func(tag string) error {
+					if err := db.AddTransactionsTag(ids, tag); err != nil {
+						return err
+					}
+					u.Info("Tagged %d transaction(s) with %s.", len(ids), tag)
+					return nil
+				}, done)
+			case "Remove Tag":
+				showBulkTextForm(u, ids, "Tag", "Remove Tag from", func(tag string) error {
+					if err := db.RemoveTransactionsTag(ids, tag); err != nil {
+						return err
+					}
+					u.Info("Removed %s from %d transaction(s).", tag, len(ids))
+					return nil
+				}, done)
+			case "Set Account":
+				showBulkTextForm(u, ids, "Account", "Set Account of", func(account string) error {
+					if err := db.SetTransactionsAccount(ids, account); err != nil {
+						return err
+					}
+					u.Info("Moved %d transaction(s) to account %s.", len(ids), account)
+					return nil
+				}, done)
+			case "Mark as Transfer":
+				if err := bulkSetCategory(u, ids, db.TransferCategory); err != nil {
+					u.Error("Error updating transactions: %v", err)
+					return
+				}
+				done()
+				u.Changed()
+			case "Delete":
+				confirmBulkDelete(u, ids, done)
+			}
+		})
+	u.Modal(modal)
+}
+
+func showBulkCategoryForm(u *ui.UI, ids []int, done func()) {
+	showBulkTextForm(u, ids, "Category", "Set Category of", func(category string) error {
+		return bulkSetCategory(u, ids, category)
+	}, done)
+}
+
+// showBulkTextForm asks for one required value and hands it to apply; the
+// form stays open when apply fails.
+func showBulkTextForm(u *ui.UI, ids []int, label, title string, apply func(value string) error, done func()) {
+	var form *tview.Form
+	form = tview.NewForm().
+		AddInputField(label, "", 20, nil, nil).
+		AddButton("Save", func() {
+			value := strings.TrimSpace(form.GetFormItemByLabel(label).(*tview.InputField).GetText())
+			if value == "" {
+				u.Error("%s is required", label)
+				return
+			}
+			if err := apply(value); err != nil {
+				u.Error("Error updating transactions: %v", err)
+				return
+			}
+			u.Pop()
+			done()
+			u.Changed()
+		}).
+		AddButton("Cancel", u.Pop)
+
+	form.SetCancelFunc(u.Pop)
+	form.SetBorder(true).SetTitle(fmt.Sprintf("[green]%s %d Transaction(s)", title, len(ids))).SetTitleAlign(tview.AlignLeft)
+	u.Push(form, nil)
+}
+
+// bulkSetCategory moves ids to category and, like a single edit, checks the
+// budgets of the category the transactions landed in.
+func bulkSetCategory(u *ui.UI, ids []int, category string) error {
+	if err := db.SetTransactionsCategory(ids, category); err != nil {
+		return err
+	}
+	u.Info("Moved %d transaction(s) to %s.", len(ids), category)
+	txs, err := db.GetTransactionsByIDs(ids)
+	if err != nil {
+		u.Error("Error checking budget alerts: %v", err)
+		return nil
+	}
+	if warnings := alert.Warnings(alert.Evaluate(alert.TouchesOf(txs)...)); len(warnings) > 0 {
+		u.Warn(warnings)
+	}
+	return nil
+}
+
+func confirmBulkDelete(u *ui.UI, ids []int, done func()) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("[red]Move %d transaction(s) to the trash?[::-]", len(ids))).
+		AddButtons([]string{"Delete", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			u.Pop()
+			if buttonLabel != "Delete" {
+				return
+			}
+			if err := db.DeleteTransactions(ids); err != nil {
+				u.Error("Delete error: %v", err)
+				return
+			}
+			done()
+			u.Changed()
+			u.Info("Moved %d transaction(s) to the trash.", len(ids))
+		})
+	u.Modal(modal)
+}
@@ -0,0 +1,207 @@
+package transaction
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"personal-finance-cli/internal/parser"
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const lastImportDirKey = "import.last_dir"
+
+// filePicker is a directory browser that lets the user mark several
+// statement files, possibly from different directories, for one import.
+type filePicker struct {
+	dir      string
+	entries  []os.DirEntry
+	selected map[string]bool
+
+	table  *tview.Table
+	status *tview.TextView
+	layout *tview.Flex
+
+	onDone   func(paths []string)
+	onCancel func()
+}
+
+func newFilePicker(dir string, onDone func(paths []string), onCancel func()) *filePicker {
+	fp := &filePicker{
+		selected: map[string]bool{},
+		onDone:   onDone,
+		onCancel: onCancel,
+	}
+
+	fp.table = tview.NewTable().SetSelectable(true, false)
+	fp.table.SetBorder(true).SetTitleAlign(tview.AlignLeft)
+	fp.status = tview.NewTextView().SetDynamicColors(true)
+
+	fp.table.SetSelectedFunc(func(row, column int) {
+		fp.activate(row)
+	})
+	fp.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape:
+			fp.onCancel()
+			return nil
+		case event.Key() == tcell.KeyRune && event.Rune() == ' ':
+			row, _ := fp.table.GetSelection()
+			fp.toggle(row)
+			return nil
+		case event.Key() == tcell.KeyRune && event.Rune() == 'a':
+			fp.toggleAll()
+			return nil
+		case event.Key() == tcell.KeyRune && event.Rune() == 'i':
+			fp.finish()
+			return nil
+		case event.Key() == tcell.KeyBackspace || event.Key() == tcell.KeyBackspace2:
+			fp.open(filepath.Dir(fp.dir))
+			return nil
+		}
+		return event
+	})
+
+	fp.layout = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(fp.table, 0, 1, true).
+		AddItem(fp.status, 1, 0, false)
+
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	fp.open(dir)
+	return fp
+}
+
+// open lists dir, keeping only sub-directories and supported statement files.
+func (fp *filePicker) open(dir string) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		abs = dir
+	}
+	all, err := os.ReadDir(abs)
+	if err != nil && fp.dir != "" {
+		fp.setStatus(fmt.Sprintf("[red]Cannot open %s: %v", abs, err))
+		return
+	}
+
+	var entries []os.DirEntry
+	for _, e := range all {
+		if e.IsDir() || parser.IsSupportedFile(e.Name()) {
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].IsDir() != entries[j].IsDir() {
+			return entries[i].IsDir()
+		}
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	fp.dir = abs
+	fp.entries = entries
+	fp.render()
+	fp.table.Select(0, 0)
+}
+
+func (fp *filePicker) render() {
+	fp.table.Clear()
+	fp.table.SetTitle(fmt.Sprintf("[green]Import: %s (Enter=Open/Toggle, Space=Toggle, a=All, i=Import, ESC=Cancel)", fp.dir))
+	fp.table.SetCell(0, 0, tview.NewTableCell("[green]../"))
+	for i, e := range fp.entries {
+		var label string
+		switch {
+		case e.IsDir():
+			label = "[green]" + tview.Escape(e.Name()) + "/"
+		case fp.selected[filepath.Join(fp.dir, e.Name())]:
+			label = tview.Escape("[x] " + e.Name())
+		default:
+			label = tview.Escape("[ ] " + e.Name())
+		}
+		fp.table.SetCell(i+1, 0, tview.NewTableCell(label).SetExpansion(1))
+	}
+	fp.setStatus(fmt.Sprintf("[green]%d file(s) selected", len(fp.selected)))
+}
+
+func (fp *filePicker) setStatus(msg string) {
+	fp.status.SetText(msg)
+}
+
+func (fp *filePicker) entryAt(row int) (os.DirEntry, bool) {
+	if row < 1 || row > len(fp.entries) {
+		return nil, false
+	}
+	return fp.entries[row-1], true
+}
+
+func (fp *filePicker) activate(row int) {
+	if row == 0 {
+		fp.open(filepath.Dir(fp.dir))
+		return
+	}
+	e, ok := fp.entryAt(row)
+	if !ok {
+		return
+	}
+	if e.IsDir() {
+		fp.open(filepath.Join(fp.dir, e.Name()))
+		return
+	}
+	fp.toggle(row)
+}
+
+func (fp *filePicker) toggle(row int) {
+	e, ok := fp.entryAt(row)
+	if !ok || e.IsDir() {
+		return
+	}
+	path := filepath.Join(fp.dir, e.Name())
+	if fp.selected[path] {
+		delete(fp.selected, path)
+	} else {
+		fp.selected[path] = true
+	}
+	fp.render()
+	fp.table.Select(row, 0)
+}
+
+// toggleAll selects every file in the current directory, or clears them
+// when they are all selected already.
+func (fp *filePicker) toggleAll() {
+	row, _ := fp.table.GetSelection()
+	allSelected := true
+	for _, e := range fp.entries {
+		if !e.IsDir() && !fp.selected[filepath.Join(fp.dir, e.Name())] {
+			allSelected = false
+			break
+		}
+	}
+	for _, e := range fp.entries {
+		if e.IsDir() {
+			continue
+		}
+		path := filepath.Join(fp.dir, e.Name())
+		if allSelected {
+			delete(fp.selected, path)
+		} else {
+			fp.selected[path] = true
+		}
+	}
+	fp.render()
+	fp.table.Select(row, 0)
+}
+
+func (fp *filePicker) finish() {
+	if len(fp.selected) == 0 {
+		fp.setStatus("[red]No files selected")
+		return
+	}
+	paths := make([]string, 0, len(fp.selected))
+	for p := range fp.selected {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	fp.onDone(paths)
+}
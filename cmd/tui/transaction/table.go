@@ -0,0 +1,331 @@
+package transaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"personal-finance-cli/cmd/tui/ui"
+	"personal-finance-cli/db"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	viewSettingsKey = "tui.transactions_view"
+	// Rows are read pageSize at a time as the table scrolls; older pages are
+	// dropped once more than maxCachedPages are held.
+	pageSize       = 200
+	maxCachedPages = 20
+)
+
+var tableColumns = []struct{ title, sort string }{
+	{"ID", db.SortID},
+	{"Amount", db.SortAmount},
+	{"Category", db.SortCategory},
+	{"Date", db.SortDate},
+	{"Account", db.SortAccount},
+	{"Description", db.SortDescription},
+}
+
+var defaultSort = db.TransactionSort{Column: db.SortDate, Desc: true}
+
+// viewSettings is the table's sort order and filter, saved between runs.
+type viewSettings struct {
+	Sort   db.TransactionSort   `json:"sort"`
+	Filter db.TransactionFilter `json:"filter"`
+}
+
+func loadViewSettings() viewSettings {
+	v := viewSettings{Sort: defaultSort}
+	raw, err := db.GetSetting(viewSettingsKey)
+	if err != nil || raw == "" {
+		return v
+	}
+	_ = json.Unmarshal([]byte(raw), &v)
+	if _, err := columnIndex(v.Sort.Column); err != nil {
+		v.Sort = defaultSort
+	}
+	return v
+}
+
+func saveViewSettings(v viewSettings) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return db.SetUIState(viewSettingsKey, string(raw))
+}
+
+func columnIndex(sort string) (int, error) {
+	for i, c := range tableColumns {
+		if c.sort == sort {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown column %q", sort)
+}
+
+// transactionContent feeds a tview.Table straight from the database: only
+// the pages of rows that are drawn get loaded, so the table stays fast with
+// tens of thousands of transactions.
+type transactionContent struct {
+	tview.TableContentReadOnly
+
+	view      viewSettings
+	totals    db.TransactionTotals
+	pages     map[int][]db.Transaction
+	selected  map[int]bool
+	highlight *regexp.Regexp
+	onError   func(error)
+	onSort    func(column int)
+}
+
+// reset re-counts the filtered rows and empties the page cache.
+func (c *transactionContent) reset() error {
+	c.pages = map[int][]db.Transaction{}
+	c.highlight = nil
+	if words := strings.Fields(c.view.Filter.Text); len(words) > 0 {
+		patterns := make([]string, len(words))
+		for i, w := range words {
+			patterns[i] = regexp.QuoteMeta(w)
+		}
+		c.highlight = regexp.MustCompile(`(?i)` + strings.Join(patterns, "|"))
+	}
+
+	totals, err := db.GetTransactionTotals(c.view.Filter)
+	if err != nil {
+		c.totals = db.TransactionTotals{}
+		return err
+	}
+	c.totals = totals
+	return nil
+}
+
+// at returns the transaction shown on table row (row 0 is the header).
+func (c *transactionContent) at(row int) (db.Transaction, bool) {
+	if row < 1 || row > c.totals.Count {
+		return db.Transaction{}, false
+	}
+	idx := row - 1
+	page, ok := c.pages[idx/pageSize]
+	if !ok {
+		if len(c.pages) >= maxCachedPages {
+			c.pages = map[int][]db.Transaction{}
+		}
+		var err error
+		page, err = db.QueryTransactions(c.view.Filter, c.view.Sort, idx/pageSize*pageSize, pageSize)
+		if err != nil {
+			c.onError(err)
+			return db.Transaction{}, false
+		}
+		c.pages[idx/pageSize] = page
+	}
+	if idx%pageSize >= len(page) {
+		return db.Transaction{}, false
+	}
+	return page[idx%pageSize], true
+}
+
+func (c *transactionContent) GetRowCount() int {
+	return c.totals.Count + 1
+}
+
+func (c *transactionContent) GetColumnCount() int {
+	return len(tableColumns)
+}
+
+func (c *transactionContent) GetCell(row, column int) *tview.TableCell {
+	if column < 0 || column >= len(tableColumns) {
+		return nil
+	}
+	if row == 0 {
+		title := tableColumns[column].title
+		if tableColumns[column].sort == c.view.Sort.Column {
+			if c.view.Sort.Desc {
+				title += " ▼"
+			} else {
+				title += " ▲"
+			}
+		}
+		return tview.NewTableCell(fmt.Sprintf("[::b][green]%d %s[::-]", column+1, title)).
+			SetSelectable(false).
+			SetClickedFunc(func() bool {
+				c.onSort(column)
+				return true
+			})
+	}
+
+	t, ok := c.at(row)
+	if !ok {
+		return nil
+	}
+	cell := c.cell(t, column)
+	if c.selected[t.ID] {
+		cell.SetBackgroundColor(tcell.ColorDarkGreen)
+	}
+	return cell
+}
+
+func (c *transactionContent) cell(t db.Transaction, column int) *tview.TableCell {
+	switch column {
+	case 0:
+		return tview.NewTableCell(strconv.Itoa(t.ID))
+	case 1:
+		amount := fmt.Sprintf("%.2f", t.Amount)
+		if t.Amount < 0 {
+			amount = "[red]" + amount
+		}
+		return tview.NewTableCell(amount).SetAlign(tview.AlignRight)
+	case 2:
+		return tview.NewTableCell(tview.Escape(t.Category))
+	case 3:
+		return tview.NewTableCell(t.Date.Format("2006-01-02"))
+	case 4:
+		return tview.NewTableCell(tview.Escape(t.Account))
+	default:
+		desc := tview.Escape(t.Description)
+		if c.highlight != nil {
+			desc = c.highlight.ReplaceAllString(desc, "[yellow::b]$0[-::-]")
+		}
+		for _, tag := range t.Tags {
+			desc += " [blue]#" + tview.Escape(tag) + "[-]"
+		}
+		return tview.NewTableCell(desc).SetExpansion(1)
+	}
+}
+
+// footerText shows the totals of the filtered rows and the active filter.
+func (c *transactionContent) footerText() string {
+	text := fmt.Sprintf("[green]%d transaction(s) | income %.2f | expenses %.2f | net %.2f",
+		c.totals.Count, c.totals.Income, c.totals.Expenses, c.totals.Net)
+	if len(c.selected) > 0 {
+		text += fmt.Sprintf(" | [::b]%d selected[::-]", len(c.selected))
+	}
+	if !c.view.Filter.IsZero() {
+		text += " | [yellow]filter: " + tview.Escape(describeFilter(c.view.Filter))
+	}
+	return text
+}
+
+func describeFilter(f db.TransactionFilter) string {
+	var parts []string
+	if f.Category != "" {
+		parts = append(parts, "category="+f.Category)
+	}
+	if f.Flow != db.FlowAll {
+		parts = append(parts, f.Flow+" only")
+	}
+	if !f.From.IsZero() {
+		parts = append(parts, "from "+f.From.Format("2006-01-02"))
+	}
+	if !f.To.IsZero() {
+		parts = append(parts, "to "+f.To.Format("2006-01-02"))
+	}
+	if f.MinAmount != nil {
+		parts = append(parts, fmt.Sprintf("amount>=%.2f", *f.MinAmount))
+	}
+	if f.MaxAmount != nil {
+		parts = append(parts, fmt.Sprintf("amount<=%.2f", *f.MaxAmount))
+	}
+	if strings.TrimSpace(f.Text) != "" {
+		parts = append(parts, fmt.Sprintf("%q", f.Text))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// selectedIDs returns the selection in ascending id order.
+func (c *transactionContent) selectedIDs() []int {
+	ids := make([]int, 0, len(c.selected))
+	for id := range c.selected {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// ------------------ Filter Form -------------------
+
+// showFilterForm edits f and hands the result to apply; Clear applies an
+// empty filter.
+func showFilterForm(u *ui.UI, f db.TransactionFilter, apply func(db.TransactionFilter)) {
+	formatDate := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format("2006-01-02")
+	}
+	formatAmount := func(a *float64) string {
+		if a == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*a, 'f', -1, 64)
+	}
+
+	flows := []string{"all", db.FlowExpenses, db.FlowIncome}
+	flow := max(slices.Index(flows, f.Flow), 0)
+
+	var form *tview.Form
+	form = tview.NewForm().
+		AddInputField("Category", f.Category, 20, nil, nil).
+		AddDropDown("Flow", flows, flow, nil).
+		AddInputField("From (YYYY-MM-DD)", formatDate(f.From), 12, nil, nil).
+		AddInputField("To (YYYY-MM-DD)", formatDate(f.To), 12, nil, nil).
+		AddInputField("Min amount", formatAmount(f.MinAmount), 12, nil, nil).
+		AddInputField("Max amount", formatAmount(f.MaxAmount), 12, nil, nil).
+		AddInputField("Text", f.Text, 30, nil, nil).
+		AddButton("Apply", func() {
+			text := func(label string) string {
+				return strings.TrimSpace(form.GetFormItemByLabel(label).(*tview.InputField).GetText())
+			}
+			var nf db.TransactionFilter
+			nf.Category = text("Category")
+			if i, _ := form.GetFormItemByLabel("Flow").(*tview.DropDown).GetCurrentOption(); i > 0 {
+				nf.Flow = flows[i]
+			}
+			nf.Text = text("Text")
+
+			for _, d := range []struct {
+				label string
+				dst   *time.Time
+			}{{"From (YYYY-MM-DD)", &nf.From}, {"To (YYYY-MM-DD)", &nf.To}} {
+				if v := text(d.label); v != "" {
+					t, err := time.Parse("2006-01-02", v)
+					if err != nil {
+						u.Error("Invalid date %q", v)
+						return
+					}
+					*d.dst = t
+				}
+			}
+			for _, a := range []struct {
+				label string
+				dst   **float64
+			}{{"Min amount", &nf.MinAmount}, {"Max amount", &nf.MaxAmount}} {
+				if v := text(a.label); v != "" {
+					amount, err := strconv.ParseFloat(v, 64)
+					if err != nil {
+						u.Error("Invalid amount %q", v)
+						return
+					}
+					*a.dst = &amount
+				}
+			}
+
+			u.Pop()
+			apply(nf)
+		}).
+		AddButton("Clear", func() {
+			u.Pop()
+			apply(db.TransactionFilter{})
+		}).
+		AddButton("Cancel", u.Pop)
+
+	form.SetCancelFunc(u.Pop)
+	form.SetBorder(true).SetTitle("[green]Filter Transactions (amounts compare the absolute value)").SetTitleAlign(tview.AlignLeft)
+	u.Push(form, nil)
+}
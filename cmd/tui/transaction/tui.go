@@ -4,211 +4,273 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"personal-finance-cli/cmd/tui/ui"
 	"personal-finance-cli/db"
+	"personal-finance-cli/internal/alert"
+	"personal-finance-cli/internal/importer"
 	"personal-finance-cli/internal/parser"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
-func RunTUI() {
-	app := tview.NewApplication()
-
-	title := tview.NewTextView().
-		SetTextAlign(tview.AlignCenter).
-		SetText("[::b][green]💰 Transactions Menu[::-]").
-		SetDynamicColors(true)
-
+func RunTUI(u *ui.UI) {
 	labels := []string{"List Transactions", "Add Transaction", "Import From File", "Back"}
 	actions := []func(){
-		func() { app.Suspend(showTransactions) },
-		func() { app.Suspend(AddInteractive) },
-		func() { app.Suspend(ImportInteractive) },
-		func() { app.Stop() },
+		func() { showTransactions(u) },
+		func() { AddInteractive(u) },
+		func() { ImportInteractive(u) },
+		u.Pop,
 	}
+	u.Push(ui.Menu("💰 Transactions Menu", labels, actions), nil)
+}
+
+// ------------------ Transaction Table -------------------
 
-	current := 0
-	buttonFlex := tview.NewFlex().SetDirection(tview.FlexRow)
-	buttons := []*tview.Button{}
+func showTransactions(u *ui.UI) {
+	showTransactionTable(u, "Transactions", loadViewSettings(), true)
+}
+
+// ShowFiltered opens the transaction table on the rows matching f, such as
+// the spending behind a budget. Sorting, filtering and bulk actions work as
+// usual, but the view is not remembered.
+func ShowFiltered(u *ui.UI, title string, f db.TransactionFilter) {
+	showTransactionTable(u, title, viewSettings{Sort: defaultSort, Filter: f}, false)
+}
+
+// showTransactionTable shows the transactions matching view; when persist is
+// set, changes to the sort and filter are saved for the next run.
+func showTransactionTable(u *ui.UI, title string, view viewSettings, persist bool) {
+	content := &transactionContent{view: view, selected: map[int]bool{}}
+	table := tview.NewTable().SetContent(content).SetSelectable(true, false).SetFixed(1, 0)
+	table.SetBorder(true).
+		SetTitle("[green]" + tview.Escape(title) + " (Enter=Edit/Delete, Space/Shift+↑↓/a=Select, b=Bulk, 1-6=Sort, f=Filter, c=Clear, /=Search)").
+		SetTitleAlign(tview.AlignCenter)
+	footer := tview.NewTextView().SetDynamicColors(true)
+	search := tview.NewInputField().SetLabel("[green]/")
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
 
-	for i, label := range labels {
-		idx := i
-		btn := tview.NewButton("[green]" + label).SetSelectedFunc(actions[idx])
-		btn.SetBorder(true)
-		buttons = append(buttons, btn)
-		buttonFlex.AddItem(btn, 3, 0, false)
+	content.onError = func(err error) {
+		u.Error("Error fetching transactions: %v", err)
 	}
 
-	highlight := func() {
-		for i, btn := range buttons {
-			if i == current {
-				btn.SetLabel("[white][green]" + labels[i] + "[::-]")
-			} else {
-				btn.SetLabel("[green]" + labels[i] + "[::-]")
-			}
+	// load re-counts the filtered rows and keeps the cursor where it was so
+	// edits refresh the table in place.
+	load := func() {
+		if err := content.reset(); err != nil {
+			u.Error("Error fetching transactions: %v", err)
 		}
+		footer.SetText(content.footerText())
+		row, _ := table.GetSelection()
+		row = min(max(row, 1), content.totals.Count)
+		table.Select(row, 0)
 	}
-
-	highlight()
-
-	layout := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(title, 5, 1, false).
-		AddItem(buttonFlex, 0, 2, true)
-
-	layout.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		switch event.Key() {
-		case tcell.KeyUp:
-			current--
-			if current < 0 {
-				current = len(buttons) - 1
+	// changeView applies a new sort or filter, saves it and starts from the top.
+	changeView := func() {
+		if persist {
+			if err := saveViewSettings(content.view); err != nil {
+				u.Error("Error saving view: %v", err)
 			}
-			highlight()
-			return nil
-		case tcell.KeyDown:
-			current++
-			if current >= len(buttons) {
-				current = 0
-			}
-			highlight()
-			return nil
-		case tcell.KeyEnter:
-			actions[current]()
-			return nil
 		}
-		return event
-	})
-
-	if err := app.SetRoot(layout, true).EnableMouse(true).Run(); err != nil {
-		fmt.Println(err)
+		table.Select(1, 0).ScrollToBeginning()
+		load()
 	}
-}
-
-// ------------------ Transaction Table -------------------
-
-func showTransactions() {
-	txs, err := db.GetTransactions()
-	if err != nil {
-		fmt.Println("Error fetching transactions:", err)
-		return
+	content.onSort = func(column int) {
+		sort := tableColumns[column].sort
+		if content.view.Sort.Column == sort {
+			content.view.Sort.Desc = !content.view.Sort.Desc
+		} else {
+			content.view.Sort = db.TransactionSort{Column: sort, Desc: sort == db.SortDate || sort == db.SortAmount}
+		}
+		changeView()
 	}
+	load()
 
-	app := tview.NewApplication()
-	table := tview.NewTable().SetSelectable(true, false)
-	table.SetBorder(true).SetTitle("[green]Transactions (Enter=Edit/Delete, ESC=Back)").SetTitleAlign(tview.AlignCenter)
+	search.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			content.view.Filter.Text = strings.TrimSpace(search.GetText())
+			changeView()
+		}
+		layout.RemoveItem(search)
+		u.App.SetFocus(table)
+	})
 
-	headers := []string{"ID", "Amount", "Category", "Date", "Description"}
-	for i, h := range headers {
-		table.SetCell(0, i, tview.NewTableCell(fmt.Sprintf("[::b][green]%s[::-]", h)).SetSelectable(false))
-	}
+	table.SetSelectedFunc(func(row, column int) {
+		if tx, ok := content.at(row); ok {
+			showTransactionActions(u, tx)
+		}
+	})
 
-	for r, t := range txs {
-		table.SetCell(r+1, 0, tview.NewTableCell(strconv.Itoa(t.ID)))
-		table.SetCell(r+1, 1, tview.NewTableCell(fmt.Sprintf("%.2f", t.Amount)))
-		table.SetCell(r+1, 2, tview.NewTableCell(t.Category))
-		table.SetCell(r+1, 3, tview.NewTableCell(t.Date.Format("2006-01-02")))
-		table.SetCell(r+1, 4, tview.NewTableCell(t.Description))
+	// anchor is the row a Shift+arrow range extends from.
+	anchor := 0
+	selectRange := func(from, to int) {
+		for r := min(from, to); r <= max(from, to); r++ {
+			if tx, ok := content.at(r); ok {
+				content.selected[tx.ID] = true
+			}
+		}
+		footer.SetText(content.footerText())
+	}
+	clearSelection := func() {
+		clear(content.selected)
+		footer.SetText(content.footerText())
 	}
 
-	table.SetSelectedFunc(func(row, column int) {
-		if row == 0 {
-			return
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		row, _ := table.GetSelection()
+		if (event.Key() == tcell.KeyUp || event.Key() == tcell.KeyDown) && event.Modifiers()&tcell.ModShift != 0 {
+			if anchor == 0 {
+				anchor = row
+			}
+			next := row + 1
+			if event.Key() == tcell.KeyUp {
+				next = row - 1
+			}
+			next = min(max(next, 1), content.totals.Count)
+			table.Select(next, 0)
+			selectRange(anchor, next)
+			return nil
+		}
+		if event.Key() != tcell.KeyRune {
+			return event
 		}
-		tx := txs[row-1]
-		showTransactionActions(tx, table, app)
+		switch r := event.Rune(); {
+		case r == ' ':
+			if tx, ok := content.at(row); ok {
+				if content.selected[tx.ID] {
+					delete(content.selected, tx.ID)
+				} else {
+					content.selected[tx.ID] = true
+				}
+				anchor = row
+				footer.SetText(content.footerText())
+				table.Select(min(row+1, content.totals.Count), 0)
+			}
+		case r == 'a':
+			ids, err := db.GetTransactionIDs(content.view.Filter)
+			if err != nil {
+				u.Error("Error selecting transactions: %v", err)
+				return nil
+			}
+			all := true
+			for _, id := range ids {
+				all = all && content.selected[id]
+			}
+			if all {
+				clearSelection()
+				return nil
+			}
+			for _, id := range ids {
+				content.selected[id] = true
+			}
+			footer.SetText(content.footerText())
+		case r == 'b':
+			if len(content.selected) == 0 {
+				u.Error("Nothing selected: Space toggles a row, Shift+arrows select a range, a selects all")
+				return nil
+			}
+			showBulkActions(u, content.selectedIDs(), func() {
+				clearSelection()
+				anchor = 0
+			})
+		case r == '/':
+			search.SetText(content.view.Filter.Text)
+			layout.AddItem(search, 1, 0, true)
+			u.App.SetFocus(search)
+		case r == 'f':
+			showFilterForm(u, content.view.Filter, func(f db.TransactionFilter) {
+				content.view.Filter = f
+				changeView()
+			})
+		case r == 'c':
+			content.view.Filter = db.TransactionFilter{}
+			changeView()
+		case r >= '1' && r < '1'+rune(len(tableColumns)):
+			content.onSort(int(r - '1'))
+		default:
+			return event
+		}
+		return nil
 	})
 
 	table.SetDoneFunc(func(key tcell.Key) {
-		if key == tcell.KeyEscape {
-			app.Stop()
+		if key != tcell.KeyEscape {
+			return
+		}
+		if len(content.selected) > 0 {
+			clearSelection()
+			anchor = 0
+			return
 		}
+		u.Pop()
 	})
 
-	if err := app.SetRoot(table, true).EnableMouse(true).Run(); err != nil {
-		fmt.Println(err)
-	}
+	u.Push(layout, load)
 }
 
 // ------------------ Transaction Modal -------------------
 
-func showTransactionActions(tx db.Transaction, parentTable *tview.Table, app *tview.Application) {
+func showTransactionActions(u *ui.UI, tx db.Transaction) {
 	modal := tview.NewModal().
 		SetText(fmt.Sprintf("[green]Transaction ID %d\nChoose an action[::-]", tx.ID)).
 		AddButtons([]string{"Edit", "Delete", "Cancel"}).
 		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			u.Pop()
 			switch buttonLabel {
 			case "Edit":
-				app.Suspend(func() { UpdateInteractive(tx) })
+				UpdateInteractive(u, tx)
 			case "Delete":
-				app.Suspend(func() {
-					if err := db.DeleteTransaction(tx.ID); err != nil {
-						fmt.Println("Delete error:", err)
-					} else {
-						fmt.Println("Transaction deleted!")
-					}
-				})
-			case "Cancel":
+				if err := db.DeleteTransaction(tx.ID); err != nil {
+					u.Error("Delete error: %v", err)
+					return
+				}
+				u.Changed()
+				u.Info("Transaction %d moved to the trash.", tx.ID)
 			}
-			app.SetRoot(parentTable, true)
 		})
 
-	app.SetRoot(modal, false)
+	u.Modal(modal)
 }
 
 // ------------------ Add / Update Forms -------------------
 
-func AddInteractive() {
-	app := tview.NewApplication()
-	var form *tview.Form
-	form = tview.NewForm().
-		AddInputField("Amount", "", 20, nil, nil).
-		AddInputField("Category", "Uncategorized", 20, nil, nil).
-		AddInputField("Description", "", 50, nil, nil).
-		AddInputField("Date (YYYY-MM-DD)", time.Now().Format("2006-01-02"), 20, nil, nil).
-		AddButton("Save", func() {
-			amountText := form.GetFormItemByLabel("Amount").(*tview.InputField).GetText()
-			category := form.GetFormItemByLabel("Category").(*tview.InputField).GetText()
-			desc := form.GetFormItemByLabel("Description").(*tview.InputField).GetText()
-			dateText := form.GetFormItemByLabel("Date (YYYY-MM-DD)").(*tview.InputField).GetText()
-
-			amount, err := strconv.ParseFloat(amountText, 64)
-			if err != nil {
-				fmt.Println("Invalid amount")
-				return
-			}
-
-			txDate, err := time.Parse("2006-01-02", dateText)
-			if err != nil {
-				fmt.Println("Invalid date")
-				return
-			}
-
-			tx := db.Transaction{
-				Amount:      amount,
-				Category:    category,
-				Description: desc,
-				Date:        txDate,
-			}
-
-			if err := db.InsertTransaction(tx); err != nil {
-				fmt.Println("Error saving transaction:", err)
-			} else {
-				fmt.Println("Transaction added!")
-			}
-			app.Stop()
-		}).
-		AddButton("Cancel", func() { app.Stop() })
+func AddInteractive(u *ui.UI) {
+	showTransactionForm(u, db.Transaction{Category: "Uncategorized", Date: time.Now()}, "[green]Add Transaction", func(tx db.Transaction) error {
+		if err := db.InsertTransaction(tx); err != nil {
+			return err
+		}
+		u.Info("Transaction added.")
+		return nil
+	})
+}
 
-	form.SetBorder(true).SetTitle("[green]Add Transaction").SetTitleAlign(tview.AlignLeft)
-	app.SetRoot(form, true).EnableMouse(true).Run()
+func UpdateInteractive(u *ui.UI, tx db.Transaction) {
+	showTransactionForm(u, tx, fmt.Sprintf("[green]Edit Transaction ID %d", tx.ID), func(tx db.Transaction) error {
+		if err := db.UpdateTransaction(tx); err != nil {
+			return err
+		}
+		u.Info("Transaction %d updated.", tx.ID)
+		return nil
+	})
 }
 
-func UpdateInteractive(tx db.Transaction) {
-	app := tview.NewApplication()
+// showTransactionForm edits a copy of tx and hands it to save. On success the
+// form closes, open screens refresh and any budget alerts go to the status
+// bar; on error the form stays open.
+func showTransactionForm(u *ui.UI, tx db.Transaction, title string, save func(db.Transaction) error) {
+	amount := ""
+	if tx.ID != 0 {
+		amount = fmt.Sprintf("%.2f", tx.Amount)
+	}
+
 	var form *tview.Form
 	form = tview.NewForm().
-		AddInputField("Amount", fmt.Sprintf("%.2f", tx.Amount), 20, nil, nil).
+		AddInputField("Amount", amount, 20, nil, nil).
 		AddInputField("Category", tx.Category, 20, nil, nil).
 		AddInputField("Description", tx.Description, 50, nil, nil).
 		AddInputField("Date (YYYY-MM-DD)", tx.Date.Format("2006-01-02"), 20, nil, nil).
@@ -220,13 +282,13 @@ func UpdateInteractive(tx db.Transaction) {
 
 			amount, err := strconv.ParseFloat(amountText, 64)
 			if err != nil {
-				fmt.Println("Invalid amount")
+				u.Error("Invalid amount")
 				return
 			}
 
 			txDate, err := time.Parse("2006-01-02", dateText)
 			if err != nil {
-				fmt.Println("Invalid date")
+				u.Error("Invalid date")
 				return
 			}
 
@@ -235,99 +297,180 @@ func UpdateInteractive(tx db.Transaction) {
 			tx.Description = desc
 			tx.Date = txDate
 
-			if err := db.UpdateTransaction(tx); err != nil {
-				fmt.Println("Error updating transaction:", err)
-			} else {
-				fmt.Println("Transaction updated!")
+			if err := save(tx); err != nil {
+				u.Error("Error saving transaction: %v", err)
+				return
+			}
+			u.Pop()
+			u.Changed()
+			if warnings := alert.Warnings(alert.Evaluate(alert.Touch{Category: tx.Category, Date: tx.Date})); len(warnings) > 0 {
+				u.Warn(warnings)
 			}
-			app.Stop()
 		}).
-		AddButton("Cancel", func() { app.Stop() })
+		AddButton("Cancel", u.Pop)
 
-	form.SetBorder(true).SetTitle(fmt.Sprintf("[green]Edit Transaction ID %d", tx.ID)).SetTitleAlign(tview.AlignLeft)
-	app.SetRoot(form, true).EnableMouse(true).Run()
+	form.SetCancelFunc(u.Pop)
+	form.SetBorder(true).SetTitle(title).SetTitleAlign(tview.AlignLeft)
+	u.Push(form, nil)
 }
 
 // ------------------ Import From File flow -------------------
 
-func ImportInteractive() {
-	app := tview.NewApplication()
-	var form *tview.Form
-	form = tview.NewForm().
-		AddInputField("File path", "", 60, nil, nil).
-		AddButton("Import", func() {
-			path := form.GetFormItemByLabel("File path").(*tview.InputField).GetText()
-			if path == "" {
-				fmt.Println("No path provided")
-				return
-			}
-			f, err := os.Open(path)
-			if err != nil {
-				fmt.Println("Failed to open file:", err)
-				return
-			}
-			defer f.Close()
+type importedFile struct {
+	path   string
+	parsed []parser.ParsedTransaction
+}
+
+func ImportInteractive(u *ui.UI) {
+	base := u.Depth()
+
+	startDir, _ := db.GetSetting(lastImportDirKey)
+	if info, err := os.Stat(startDir); err != nil || !info.IsDir() {
+		startDir = ""
+	}
+
+	picker := newFilePicker(startDir, func(paths []string) {
+		_ = db.SetUIState(lastImportDirKey, filepath.Dir(paths[len(paths)-1]))
 
-			parsed, err := parser.DetectAndParse(f, filepath.Base(path))
+		var batch []importedFile
+		var failures []string
+		for _, path := range paths {
+			parsed, err := parser.ParseFileByPath(path)
 			if err != nil {
-				fmt.Println("Parse error:", err)
-				return
-			}
-			if len(parsed) == 0 {
-				fmt.Println("No transactions parsed")
-				return
+				failures = append(failures, fmt.Sprintf("%s: %v", filepath.Base(path), err))
+				continue
 			}
+			batch = append(batch, importedFile{path: path, parsed: parsed})
+		}
+		showImportReview(u, base, batch, failures)
+	}, u.Pop)
 
-			if err := parser.InsertParsedTransactions(parsed); err != nil {
-				fmt.Println("Error inserting transactions:", err)
-				return
-			}
+	u.Push(picker.layout, nil)
+}
 
-			catSet := map[string]struct{}{}
-			for _, p := range parsed {
-				if p.Amount < 0 {
-					catSet[p.Category] = struct{}{}
-				}
-			}
+// showImportReview lists every parsed row of the batch so the user can check
+// it before anything is written; confirming inserts all files together and
+// returns to the screen the import started from.
+func showImportReview(u *ui.UI, base int, batch []importedFile, failures []string) {
+	var all []parser.ParsedTransaction
+	for _, f := range batch {
+		all = append(all, f.parsed...)
+	}
 
-			var summaryLines []string
-			for cat := range catSet {
-				budgets, err := db.GetBudgets()
-				if err != nil {
-					continue
+	if len(all) == 0 {
+		msg := "No transactions parsed"
+		for _, f := range failures {
+			msg += "\n" + f
+		}
+		u.Message("[red]" + tview.Escape(msg))
+		return
+	}
+
+	table := tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	table.SetBorder(true).
+		SetTitle(fmt.Sprintf("[green]Review %d transactions from %d file(s) (Enter=Import, ESC=Cancel)", len(all), len(batch))).
+		SetTitleAlign(tview.AlignCenter)
+
+	headers := []string{"File", "Date", "Amount", "Category", "Description"}
+	for i, h := range headers {
+		table.SetCell(0, i, tview.NewTableCell(fmt.Sprintf("[::b][green]%s[::-]", h)).SetSelectable(false))
+	}
+
+	r := 1
+	for _, f := range batch {
+		for _, p := range f.parsed {
+			table.SetCell(r, 0, tview.NewTableCell(tview.Escape(filepath.Base(f.path))))
+			table.SetCell(r, 1, tview.NewTableCell(p.Date.Format("2006-01-02")))
+			table.SetCell(r, 2, tview.NewTableCell(fmt.Sprintf("%.2f", p.Amount)))
+			table.SetCell(r, 3, tview.NewTableCell(tview.Escape(p.Category)))
+			table.SetCell(r, 4, tview.NewTableCell(tview.Escape(p.Description)))
+			r++
+		}
+	}
+
+	table.SetSelectedFunc(func(row, column int) {
+		text := fmt.Sprintf("[green]Import %d transactions?[::-]", len(all))
+		if len(failures) > 0 {
+			text += fmt.Sprintf("\n[red]%d file(s) could not be parsed and will be skipped[::-]", len(failures))
+		}
+		confirm := tview.NewModal().
+			SetText(text).
+			AddButtons([]string{"Import", "Cancel"}).
+			SetDoneFunc(func(i int, lbl string) {
+				if lbl != "Import" {
+					u.Pop()
+					return
 				}
-				for _, b := range budgets {
-					if b.Category != cat {
-						continue
-					}
-					rem, err := db.GetBudgetRemaining(b)
-					if err != nil {
-						continue
-					}
-					summaryLines = append(summaryLines, fmt.Sprintf("- %s (%s): remaining %.2f (limit %.2f)", b.Category, b.Period, rem, b.Amount))
+				u.PopTo(base)
+				res := importer.ImportParsed(fmt.Sprintf("%d file(s)", len(batch)), all)
+				if res.Err != nil {
+					u.Error("Error inserting transactions: %v", res.Err)
+					return
 				}
-			}
+				u.Changed()
+				u.Info("Imported %d transactions from %d file(s), skipped %d duplicate(s).", res.Imported, len(batch), res.Duplicates)
 
-			msg := fmt.Sprintf("Imported %d transactions\n", len(parsed))
-			if len(summaryLines) > 0 {
-				msg += "Updated budgets:\n"
-				for _, l := range summaryLines {
-					msg += l + "\n"
+				msg := fmt.Sprintf("Imported %d transactions from %d file(s)\n", res.Imported, len(batch))
+				if res.Duplicates > 0 {
+					msg += fmt.Sprintf("Skipped %d already stored\n", res.Duplicates)
 				}
-			} else {
-				msg += "No matching budgets were affected."
-			}
+				for _, f := range failures {
+					msg += "Skipped " + f + "\n"
+				}
+				msg += budgetSummary(all)
+				for _, w := range res.Warnings {
+					msg += "\n" + w
+				}
+				msg = "[green]" + tview.Escape(msg)
+				for _, a := range res.Anomalies {
+					msg += "\n[yellow]" + tview.Escape(a) + "[green]"
+				}
+				u.Message(msg)
+			})
+		u.Modal(confirm)
+	})
 
-			m := tview.NewModal().
-				SetText("[green]" + msg + "[::-]").
-				AddButtons([]string{"OK"}).
-				SetDoneFunc(func(i int, lbl string) {
-					app.Stop()
-				})
-			app.SetRoot(m, false)
-		}).
-		AddButton("Cancel", func() { app.Stop() })
+	table.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			u.PopTo(base)
+		}
+	})
+
+	u.Push(table, nil)
+}
+
+func budgetSummary(parsed []parser.ParsedTransaction) string {
+	catSet := map[string]struct{}{}
+	for _, p := range parsed {
+		if p.Amount < 0 {
+			catSet[p.Category] = struct{}{}
+		}
+	}
 
-	form.SetBorder(true).SetTitle("[green]Import Transactions from File").SetTitleAlign(tview.AlignLeft)
-	app.SetRoot(form, true).EnableMouse(true).Run()
+	var summaryLines []string
+	for cat := range catSet {
+		budgets, err := db.GetBudgets()
+		if err != nil {
+			continue
+		}
+		for _, b := range budgets {
+			if b.Category != cat {
+				continue
+			}
+			rem, err := db.GetBudgetRemaining(b)
+			if err != nil {
+				continue
+			}
+			summaryLines = append(summaryLines, fmt.Sprintf("- %s (%s): remaining %.2f (limit %.2f)", b.Category, b.Period, rem, b.Amount))
+		}
+	}
+
+	if len(summaryLines) == 0 {
+		return "No matching budgets were affected."
+	}
+	msg := "Updated budgets:\n"
+	for _, l := range summaryLines {
+		msg += l + "\n"
+	}
+	return msg
 }
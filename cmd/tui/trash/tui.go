@@ -0,0 +1,133 @@
+package trash
+
+import (
+	"fmt"
+	"personal-finance-cli/cmd/tui/ui"
+	"personal-finance-cli/db"
+	"strconv"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// RunTUI lists deleted transactions and budgets so they can be restored or
+// purged for good.
+func RunTUI(u *ui.UI) {
+	header := tview.NewTextView().SetDynamicColors(true).SetTextAlign(tview.AlignCenter)
+	table := tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	table.SetBorder(true).SetTitle("[green]Trash (Enter=Restore/Purge, E=Empty trash, ESC=Back)").SetTitleAlign(tview.AlignCenter)
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(header, 1, 0, false).
+		AddItem(table, 0, 1, true)
+
+	var items []db.TrashItem
+	load := func() {
+		var err error
+		items, err = db.GetTrash()
+		if err != nil {
+			u.Error("Error fetching trash: %v", err)
+			return
+		}
+		days, err := db.TrashRetentionDays()
+		if err != nil {
+			u.Error("%v", err)
+			return
+		}
+		retention := fmt.Sprintf("purged after %d day(s)", days)
+		if days == 0 {
+			retention = "kept until purged"
+		}
+		header.SetText(fmt.Sprintf("[::b][green]%d item(s) in the trash, %s[::-]", len(items), retention))
+
+		row, _ := table.GetSelection()
+		table.Clear()
+		headers := []string{"Type", "ID", "Deleted", "Item"}
+		for i, h := range headers {
+			table.SetCell(0, i, tview.NewTableCell(fmt.Sprintf("[::b][green]%s[::-]", h)).SetSelectable(false))
+		}
+		for r, it := range items {
+			table.SetCell(r+1, 0, tview.NewTableCell(it.Kind))
+			table.SetCell(r+1, 1, tview.NewTableCell(strconv.Itoa(it.ID)))
+			table.SetCell(r+1, 2, tview.NewTableCell(it.DeletedAt.Local().Format("2006-01-02 15:04")))
+			table.SetCell(r+1, 3, tview.NewTableCell(tview.Escape(it.Summary)).SetExpansion(1))
+		}
+		if row < 1 {
+			row = 1
+		}
+		if row > len(items) {
+			row = len(items)
+		}
+		table.Select(row, 0)
+	}
+	load()
+
+	table.SetSelectedFunc(func(row, column int) {
+		if row == 0 || row > len(items) {
+			return
+		}
+		showItemActions(u, items[row-1])
+	})
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && event.Rune() == 'E' {
+			if len(items) > 0 {
+				confirmEmpty(u, len(items))
+			}
+			return nil
+		}
+		return event
+	})
+	table.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			u.Pop()
+		}
+	})
+
+	u.Push(layout, load)
+}
+
+func showItemActions(u *ui.UI, it db.TrashItem) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("[green]%s %d\n%s\nChoose an action[::-]", it.Kind, it.ID, tview.Escape(it.Summary))).
+		AddButtons([]string{"Restore", "Purge", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			u.Pop()
+			switch buttonLabel {
+			case "Restore":
+				if err := db.RestoreFromTrash(it.Kind, it.ID); err != nil {
+					u.Error("Restore error: %v", err)
+					return
+				}
+				u.Changed()
+				u.Info("Restored %s %d.", it.Kind, it.ID)
+			case "Purge":
+				if err := db.PurgeFromTrash(it.Kind, it.ID); err != nil {
+					u.Error("Purge error: %v", err)
+					return
+				}
+				u.Changed()
+				u.Info("Purged %s %d for good.", it.Kind, it.ID)
+			}
+		})
+	u.Modal(modal)
+}
+
+func confirmEmpty(u *ui.UI, n int) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("[red]Permanently remove all %d item(s)?\nThis cannot be undone.[::-]", n)).
+		AddButtons([]string{"Empty", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			u.Pop()
+			if buttonLabel != "Empty" {
+				return
+			}
+			purged, err := db.PurgeTrash(time.Time{})
+			if err != nil {
+				u.Error("Purge error: %v", err)
+				return
+			}
+			u.Changed()
+			u.Info("Purged %d item(s).", purged)
+		})
+	u.Modal(modal)
+}
@@ -0,0 +1,240 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"personal-finance-cli/db"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// UI is the single tview application shared by every screen. Screens are
+// kept on a stack of pages: Push opens a screen over the current one, Modal
+// overlays a dialog, and Pop goes back. After a change to the data, Changed
+// refreshes every open screen in place.
+type UI struct {
+	App    *tview.Application
+	pages  *tview.Pages
+	status *tview.TextView
+	stack  []screen
+	nextID int
+}
+
+type screen struct {
+	name    string
+	modal   bool
+	refresh func()
+}
+
+func New() *UI {
+	u := &UI{
+		App:    tview.NewApplication(),
+		pages:  tview.NewPages(),
+		status: tview.NewTextView().SetDynamicColors(true),
+	}
+	root := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(u.pages, 0, 1, true).
+		AddItem(u.status, 1, 0, false)
+	u.App.SetRoot(root, true).EnableMouse(true)
+	return u
+}
+
+func (u *UI) Run() error {
+	return u.App.Run()
+}
+
+// Push shows p full screen on top of the stack. refresh, if not nil, reloads
+// the screen's data and is called by Changed.
+func (u *UI) Push(p tview.Primitive, refresh func()) {
+	for _, s := range u.stack {
+		u.pages.HidePage(s.name)
+	}
+	u.add(p, false, refresh)
+}
+
+// Modal shows p over the current screen, which stays visible behind it.
+func (u *UI) Modal(p tview.Primitive) {
+	u.add(p, true, nil)
+}
+
+func (u *UI) add(p tview.Primitive, modal bool, refresh func()) {
+	u.nextID++
+	s := screen{name: fmt.Sprintf("screen-%d", u.nextID), modal: modal, refresh: refresh}
+	u.stack = append(u.stack, s)
+	u.pages.AddPage(s.name, p, !modal, true)
+	u.App.SetFocus(p)
+}
+
+// Pop closes the top screen; the app stops when the last one is closed.
+func (u *UI) Pop() {
+	top, ok := u.top()
+	if !ok {
+		return
+	}
+	u.stack = u.stack[:len(u.stack)-1]
+	u.pages.RemovePage(top.name)
+	if len(u.stack) == 0 {
+		u.App.Stop()
+		return
+	}
+
+	// Re-show every screen from the topmost full-screen one up.
+	for i := len(u.stack) - 1; i >= 0; i-- {
+		u.pages.ShowPage(u.stack[i].name)
+		if !u.stack[i].modal {
+			break
+		}
+	}
+	_, p := u.pages.GetFrontPage()
+	u.App.SetFocus(p)
+}
+
+// Depth is the number of open screens; PopTo closes screens until only
+// depth remain.
+func (u *UI) Depth() int {
+	return len(u.stack)
+}
+
+func (u *UI) PopTo(depth int) {
+	for len(u.stack) > depth {
+		u.Pop()
+	}
+}
+
+func (u *UI) top() (screen, bool) {
+	if len(u.stack) == 0 {
+		return screen{}, false
+	}
+	return u.stack[len(u.stack)-1], true
+}
+
+// Visible reports whether p is the screen on top, with no dialog over it.
+func (u *UI) Visible(p tview.Primitive) bool {
+	_, front := u.pages.GetFrontPage()
+	return front == p
+}
+
+// Changed reloads every open screen after the data was modified.
+func (u *UI) Changed() {
+	for _, s := range u.stack {
+		if s.refresh != nil {
+			s.refresh()
+		}
+	}
+}
+
+// Info, Warn and Error show a message in the status bar until the next one.
+func (u *UI) Info(format string, args ...any) {
+	u.setStatus("green", fmt.Sprintf(format, args...))
+}
+
+func (u *UI) Warn(lines []string) {
+	u.setStatus("yellow", strings.Join(lines, " | "))
+}
+
+func (u *UI) Error(format string, args ...any) {
+	u.setStatus("red", fmt.Sprintf(format, args...))
+}
+
+func (u *UI) setStatus(color, msg string) {
+	u.status.SetText(fmt.Sprintf("[%s]%s", color, tview.Escape(msg)))
+}
+
+// Message shows a dialog with an OK button that closes it.
+func (u *UI) Message(text string) {
+	m := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{"OK"}).
+		SetDoneFunc(func(i int, lbl string) { u.Pop() })
+	u.Modal(m)
+}
+
+// Menu builds a titled column of buttons navigated with the arrow keys, as
+// used by every menu screen.
+func Menu(title string, labels []string, actions []func()) tview.Primitive {
+	heading := tview.NewTextView().
+		SetTextAlign(tview.AlignCenter).
+		SetText("[::b][green]" + title + "[::-]").
+		SetDynamicColors(true)
+
+	current := 0
+	buttonFlex := tview.NewFlex().SetDirection(tview.FlexRow)
+	buttons := []*tview.Button{}
+
+	for i, label := range labels {
+		idx := i
+		btn := tview.NewButton("[green]" + label).SetSelectedFunc(actions[idx])
+		btn.SetBorder(true)
+		buttons = append(buttons, btn)
+		buttonFlex.AddItem(btn, 3, 0, false)
+	}
+
+	highlight := func() {
+		for i, btn := range buttons {
+			if i == current {
+				btn.SetLabel("[white][green]" + labels[i] + "[::-]")
+			} else {
+				btn.SetLabel("[green]" + labels[i] + "[::-]")
+			}
+		}
+	}
+
+	highlight()
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(heading, 5, 1, false).
+		AddItem(buttonFlex, 0, 2, true)
+
+	layout.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyUp:
+			current--
+			if current < 0 {
+				current = len(buttons) - 1
+			}
+			highlight()
+			return nil
+		case tcell.KeyDown:
+			current++
+			if current >= len(buttons) {
+				current = 0
+			}
+			highlight()
+			return nil
+		case tcell.KeyEnter:
+			actions[current]()
+			return nil
+		}
+		return event
+	})
+	return layout
+}
+
+// ProgressColor mirrors "budget status": spending limits turn yellow at 80%
+// and red over 100%, while income and savings goals turn green once reached.
+func ProgressColor(kind string, pct float64) string {
+	if kind == db.KindIncome || kind == db.KindSavings {
+		if pct >= 100 {
+			return "green"
+		}
+		return "yellow"
+	}
+	switch {
+	case pct > 100:
+		return "red"
+	case pct >= 80:
+		return "yellow"
+	default:
+		return "green"
+	}
+}
+
+// ProgressBar draws pct (capped at 100) as a bar of width cells in the
+// budget kind's progress colour.
+func ProgressBar(kind string, pct float64, width int) string {
+	filled := int(math.Round(math.Min(math.Max(pct, 0), 100) / 100 * float64(width)))
+	return fmt.Sprintf("[%s]%s[gray]%s[-]", ProgressColor(kind, pct),
+		strings.Repeat("█", filled), strings.Repeat("░", width-filled))
+}
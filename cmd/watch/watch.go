@@ -0,0 +1,83 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"personal-finance-cli/db"
+	"personal-finance-cli/internal/importer"
+
+	"github.com/spf13/cobra"
+)
+
+const watchDirKey = "watch.dir"
+
+var (
+	watchDir      string
+	watchInterval time.Duration
+	watchOnce     bool
+)
+
+var WatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Import statement files dropped into an inbox directory",
+	Long: "Polls a directory for new .csv/.ofx/.qfx statements, imports them while skipping duplicates, " +
+		"and moves each file to archive/ or failed/. Results are appended to import.log in the same directory.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if watchInterval <= 0 {
+			return fmt.Errorf("interval must be positive")
+		}
+		dir := watchDir
+		if dir == "" {
+			saved, err := db.GetSetting(watchDirKey)
+			if err != nil {
+				return err
+			}
+			dir = saved
+		}
+		if dir == "" {
+			return fmt.Errorf("no inbox directory configured; pass --dir once to set it")
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return fmt.Errorf("inbox %q is not a directory", dir)
+		}
+		if watchDir != "" {
+			if err := db.SetSetting(watchDirKey, watchDir); err != nil {
+				return err
+			}
+		}
+
+		w := &importer.Watcher{Dir: dir, Settle: 2 * time.Second, Out: os.Stdout}
+		if watchOnce {
+			w.Settle = 0
+			_, err := w.Scan()
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		fmt.Printf("Watching %s every %s (Ctrl+C to stop)\n", dir, watchInterval)
+		ticker := time.NewTicker(watchInterval)
+		defer ticker.Stop()
+		for {
+			if _, err := w.Scan(); err != nil {
+				fmt.Println("Watch error:", err)
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+func init() {
+	WatchCmd.Flags().StringVarP(&watchDir, "dir", "d", "", "Inbox directory (remembered for later runs)")
+	WatchCmd.Flags().DurationVarP(&watchInterval, "interval", "n", 10*time.Second, "Polling interval")
+	WatchCmd.Flags().BoolVar(&watchOnce, "once", false, "Process the inbox once and exit (for cron)")
+}
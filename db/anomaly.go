@@ -0,0 +1,196 @@
+package db
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// -------------------- Anomaly detection --------------------
+
+const (
+	AnomalyOutlier     = "outlier"
+	AnomalyNewMerchant = "new-merchant"
+	AnomalyDuplicate   = "duplicate"
+)
+
+const (
+	// anomalyMinHistory is how many expenses a category or payee needs
+	// before its distribution is trusted.
+	anomalyMinHistory = 5
+	// anomalyCutoff is the robust z-score (distance from the median in
+	// scaled median absolute deviations) above which an amount is unusual.
+	anomalyCutoff = 3.5
+	// largeChargePercentile marks a first charge from a new payee as large
+	// when it is above this share of all expenses.
+	largeChargePercentile = 0.9
+)
+
+type Anomaly struct {
+	ID          int       `json:"id"`
+	Date        time.Time `json:"date"`
+	Description string    `json:"description"`
+	Category    string    `json:"category"`
+	Amount      float64   `json:"amount"`
+	Kind        string    `json:"kind"`
+	Reason      string    `json:"reason"`
+}
+
+func (a Anomaly) Message() string {
+	return fmt.Sprintf("Unusual transaction %s %s %.2f: %s", a.Date.Format("2006-01-02"), a.Description, a.Amount, a.Reason)
+}
+
+type spread struct {
+	median, mad float64
+	n           int
+}
+
+func spreadOf(values []float64) spread {
+	m := median(values)
+	dev := make([]float64, len(values))
+	for i, v := range values {
+		dev[i] = math.Abs(v - m)
+	}
+	// 1.4826 scales the MAD to match a standard deviation for normal data.
+	return spread{median: m, mad: 1.4826 * median(dev), n: len(values)}
+}
+
+// unusual reports whether v is far above the distribution and by how many
+// times the median.
+func (s spread) unusual(v float64) (bool, float64) {
+	if s.n < anomalyMinHistory || s.median <= 0 || v <= s.median {
+		return false, 0
+	}
+	// With identical amounts the MAD is zero; fall back to 50% above median.
+	if s.mad == 0 {
+		return v > s.median*1.5, v / s.median
+	}
+	return (v-s.median)/s.mad > anomalyCutoff, v / s.median
+}
+
+// DetectAnomalies flags expenses (not transfers) dated in [from, to] that are far above the
+// usual amount for their category or payee, that are the large first charge
+// of a payee never seen before, or that repeat an earlier charge of the same
+// payee and amount on the same day. The distributions use the whole history.
+func DetectAnomalies(from, to time.Time) ([]Anomaly, error) {
+	rows, err := database.Query(`SELECT id, amount, description, category, date FROM transactions WHERE amount < 0 AND deleted_at IS NULL AND ` + notTransfer + ` ORDER BY date, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var txs []Transaction
+	for rows.Next() {
+		var t Transaction
+		var dateStr string
+		if err := rows.Scan(&t.ID, &t.Amount, &t.Description, &t.Category, &dateStr); err != nil {
+			return nil, err
+		}
+		if t.Date, err = time.Parse("2006-01-02", dateStr); err != nil {
+			continue
+		}
+		txs = append(txs, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	byCategory := map[string][]float64{}
+	byPayee := map[string][]float64{}
+	firstOfPayee := map[string]int{}
+	firstOfDay := map[string]int{}
+	all := make([]float64, 0, len(txs))
+	for _, t := range txs {
+		payee := normalizePayee(t.Description)
+		byCategory[t.Category] = append(byCategory[t.Category], -t.Amount)
+		byPayee[payee] = append(byPayee[payee], -t.Amount)
+		if _, ok := firstOfPayee[payee]; !ok {
+			firstOfPayee[payee] = t.ID
+		}
+		if _, ok := firstOfDay[duplicateKey(t, payee)]; !ok {
+			firstOfDay[duplicateKey(t, payee)] = t.ID
+		}
+		all = append(all, -t.Amount)
+	}
+	sort.Float64s(all)
+	large := math.Inf(1)
+	if len(all) >= anomalyMinHistory {
+		large = all[int(float64(len(all)-1)*largeChargePercentile)]
+	}
+
+	categoryStats := map[string]spread{}
+	for c, v := range byCategory {
+		categoryStats[c] = spreadOf(v)
+	}
+	payeeStats := map[string]spread{}
+	for p, v := range byPayee {
+		payeeStats[p] = spreadOf(v)
+	}
+
+	var anomalies []Anomaly
+	for _, t := range txs {
+		if t.Date.Before(from) || t.Date.After(to) {
+			continue
+		}
+		payee := normalizePayee(t.Description)
+		v := -t.Amount
+		flag := func(kind, reason string) {
+			anomalies = append(anomalies, Anomaly{ID: t.ID, Date: t.Date, Description: t.Description,
+				Category: t.Category, Amount: t.Amount, Kind: kind, Reason: reason})
+		}
+
+		ps, cs := payeeStats[payee], categoryStats[t.Category]
+		if payee == "" {
+			ps = spread{}
+		}
+		if ok, x := ps.unusual(v); ok {
+			flag(AnomalyOutlier, fmt.Sprintf("%.1fx the usual %.2f at this payee (%d charges)", x, ps.median, ps.n))
+		} else if ok, x := cs.unusual(v); ok {
+			flag(AnomalyOutlier, fmt.Sprintf("%.1fx the usual %.2f in %s (%d expenses)", x, cs.median, t.Category, cs.n))
+		}
+		if payee != "" && firstOfPayee[payee] == t.ID && v > large {
+			flag(AnomalyNewMerchant, fmt.Sprintf("first charge from this payee and above %.2f, larger than %.0f%% of all expenses", large, largeChargePercentile*100))
+		}
+		if first := firstOfDay[duplicateKey(t, payee)]; first != t.ID {
+			flag(AnomalyDuplicate, fmt.Sprintf("same payee and amount as transaction %d on the same day", first))
+		}
+	}
+	return anomalies, nil
+}
+
+func duplicateKey(t Transaction, payee string) string {
+	return fmt.Sprintf("%s|%s|%.2f", t.Date.Format("2006-01-02"), payee, t.Amount)
+}
+
+// AnomaliesAmong runs DetectAnomalies over the dates of txs and keeps only
+// the flags raised for those transactions, e.g. the rows of one import.
+func AnomaliesAmong(txs []Transaction) ([]Anomaly, error) {
+	if len(txs) == 0 {
+		return nil, nil
+	}
+	from, to := txs[0].Date, txs[0].Date
+	keys := map[string]bool{}
+	for _, t := range txs {
+		if t.Date.Before(from) {
+			from = t.Date
+		}
+		if t.Date.After(to) {
+			to = t.Date
+		}
+		keys[fmt.Sprintf("%s|%.2f|%s", t.Date.Format("2006-01-02"), t.Amount, t.Description)] = true
+	}
+
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	found, err := DetectAnomalies(from, to)
+	if err != nil {
+		return nil, err
+	}
+	var matched []Anomaly
+	for _, a := range found {
+		if keys[fmt.Sprintf("%s|%.2f|%s", a.Date.Format("2006-01-02"), a.Amount, a.Description)] {
+			matched = append(matched, a)
+		}
+	}
+	return matched, nil
+}
@@ -0,0 +1,497 @@
+package db
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// -------------------- Audit log, undo and redo --------------------
+
+// Every insert, update and delete of user data (transactions, budgets and
+// with them envelope assignments, budget templates, holdings and valuations,
+// recurring templates and settings) is logged with a JSON snapshot of the row before
+// and after, keyed by its rowid. Derived state (fired alerts, the search
+// index) and UI state are not logged. Entries are grouped into
+// changes, one per user action (a bulk edit or a whole import is a single
+// change), which undo and redo replay backwards and forwards. Making a new
+// change after an undo marks the undone changes as discarded, which drops
+// them from the redo stack; they stay in the history marked as undone.
+
+const (
+	OriginCLI       = "cli"
+	OriginTUI       = "tui"
+	OriginImport    = "import"
+	OriginRecurring = "recurring"
+	OriginRule      = "rule"
+)
+
+// auditedTables are the tables whose rows undo and redo may rewrite.
+var auditedTables = []string{
+	"transactions", "budgets", "budget_templates", "holdings", "valuations",
+	"recurring", "recurring_amounts", "recurring_instances", "settings",
+}
+
+var origin = OriginCLI
+
+// SetOrigin sets what later changes are attributed to and returns the
+// previous origin so it can be restored.
+func SetOrigin(o string) string {
+	prev := origin
+	origin = o
+	return prev
+}
+
+// group is the change that Group collects entries into; id stays 0 until
+// the first entry has been committed.
+var group struct {
+	active  bool
+	summary string
+	id      int64
+}
+
+// Group records every mutation made by fn as one change described by
+// summary, so it is undone in one step. Nested groups join the outer one.
+func Group(summary string, fn func() error) error {
+	if group.active {
+		return fn()
+	}
+	group.active, group.summary, group.id = true, summary, 0
+	defer func() { group.active, group.summary, group.id = false, "", 0 }()
+	return fn()
+}
+
+// change logs the mutations made through one database transaction.
+type change struct {
+	tx      *sql.Tx
+	summary string
+	id      int64
+}
+
+// record runs fn in a database transaction whose mutations are logged as
+// one change described by summary, or as part of the current Group.
+func record(summary string, fn func(c *change) error) error {
+	tx, err := database.Begin()
+	if err != nil {
+		return err
+	}
+	c := &change{tx: tx, summary: summary}
+	if group.active {
+		c.id = group.id
+	}
+	if err := fn(c); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if group.active {
+		group.id = c.id
+	}
+	return nil
+}
+
+// exec runs query against the row of table with the given rowid, or inserts
+// a row when id is 0, logs the row before and after, and returns the rowid.
+// An insert that was ignored returns 0.
+func (c *change) exec(table string, id int64, query string, args ...any) (int64, error) {
+	before, err := snapshot(c.tx, table, id)
+	if err != nil {
+		return 0, err
+	}
+	res, err := c.tx.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	if id == 0 {
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return 0, err
+		}
+		if id, err = res.LastInsertId(); err != nil {
+			return 0, err
+		}
+	}
+	after, err := snapshot(c.tx, table, id)
+	if err != nil {
+		return 0, err
+	}
+	oldValue, newValue := marshalRow(before), marshalRow(after)
+	if oldValue == newValue {
+		return id, nil
+	}
+
+	if c.id == 0 {
+		summary := c.summary
+		if group.active {
+			summary = group.summary
+		}
+		res, err := c.tx.Exec(`INSERT INTO audit_changes (at, origin, summary) VALUES (?, ?, ?)`,
+			time.Now().Format(time.RFC3339), origin, summary)
+		if err != nil {
+			return 0, err
+		}
+		if c.id, err = res.LastInsertId(); err != nil {
+			return 0, err
+		}
+		if _, err := c.tx.Exec(`UPDATE audit_changes SET discarded = 1 WHERE undone = 1 AND discarded = 0`); err != nil {
+			return 0, err
+		}
+	}
+	_, err = c.tx.Exec(`INSERT INTO audit_entries (change_id, entity, entity_id, old_value, new_value) VALUES (?, ?, ?, ?, ?)`,
+		c.id, table, id, oldValue, newValue)
+	return id, err
+}
+
+// rowids returns the rowids of the rows of table matching cond.
+func (c *change) rowids(table, cond string, args ...any) ([]int64, error) {
+	rows, err := c.tx.Query(`SELECT rowid FROM `+table+` WHERE `+cond, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// rowid returns the rowid of the row of table matching cond, or 0 when there
+// is none, so an upsert can be logged as an update of that row.
+func (c *change) rowid(table, cond string, args ...any) (int64, error) {
+	ids, err := c.rowids(table, cond, args...)
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// deleteWhere deletes the rows of table matching cond one by one so each is
+// logged. Child rows must be deleted first rather than left to ON DELETE
+// CASCADE, which the log would not see.
+func (c *change) deleteWhere(table, cond string, args ...any) error {
+	ids, err := c.rowids(table, cond, args...)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if _, err := c.exec(table, id, `DELETE FROM `+table+` WHERE rowid = ?`, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshot reads every column of one row, or nil when it does not exist.
+func snapshot(tx *sql.Tx, table string, id int64) (map[string]any, error) {
+	if id == 0 {
+		return nil, nil
+	}
+	rows, err := tx.Query(`SELECT * FROM `+table+` WHERE rowid = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	values := make([]any, len(cols))
+	ptrs := make([]any, len(cols))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return nil, err
+	}
+	row := map[string]any{}
+	for i, col := range cols {
+		if b, ok := values[i].([]byte); ok {
+			values[i] = string(b)
+		}
+		row[col] = values[i]
+	}
+	return row, rows.Close()
+}
+
+func marshalRow(row map[string]any) any {
+	if row == nil {
+		return nil
+	}
+	b, _ := json.Marshal(row)
+	return string(b)
+}
+
+func unmarshalRow(s sql.NullString) (map[string]any, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader([]byte(s.String)))
+	dec.UseNumber()
+	var row map[string]any
+	if err := dec.Decode(&row); err != nil {
+		return nil, err
+	}
+	for k, v := range row {
+		if n, ok := v.(json.Number); ok {
+			if i, err := n.Int64(); err == nil {
+				row[k] = i
+			} else {
+				row[k], _ = n.Float64()
+			}
+		}
+	}
+	return row, nil
+}
+
+// AuditEntry is one row's state before and after a change; Old is nil for
+// inserts and New is nil for deletes.
+type AuditEntry struct {
+	Table string         `json:"table"`
+	RowID int64          `json:"row_id"`
+	Old   map[string]any `json:"old"`
+	New   map[string]any `json:"new"`
+}
+
+func (e AuditEntry) Action() string {
+	switch {
+	case e.Old == nil:
+		return "insert"
+	case e.New == nil:
+		return "delete"
+	}
+	return "update"
+}
+
+// Diff describes the entry: the changed columns of an update, or the whole
+// row that was inserted or deleted.
+func (e AuditEntry) Diff() string {
+	var parts []string
+	switch e.Action() {
+	case "insert":
+		parts = describeRow(e.New)
+	case "delete":
+		parts = describeRow(e.Old)
+	default:
+		for _, col := range sortedColumns(e.New) {
+			oldV, newV := fmt.Sprint(e.Old[col]), fmt.Sprint(e.New[col])
+			if oldV != newV {
+				parts = append(parts, fmt.Sprintf("%s: %s → %s", col, oldV, newV))
+			}
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func describeRow(row map[string]any) []string {
+	var parts []string
+	for _, col := range sortedColumns(row) {
+		if col != "id" && row[col] != nil {
+			parts = append(parts, fmt.Sprintf("%s=%v", col, row[col]))
+		}
+	}
+	return parts
+}
+
+func sortedColumns(row map[string]any) []string {
+	cols := make([]string, 0, len(row))
+	for col := range row {
+		cols = append(cols, col)
+	}
+	slices.Sort(cols)
+	return cols
+}
+
+type Change struct {
+	ID      int64        `json:"id"`
+	At      time.Time    `json:"at"`
+	Origin  string       `json:"origin"`
+	Summary string       `json:"summary"`
+	Undone  bool         `json:"undone"`
+	Entries []AuditEntry `json:"entries"`
+}
+
+// GetHistory returns the latest limit changes, newest first.
+func GetHistory(limit int) ([]Change, error) {
+	rows, err := database.Query(`SELECT id, at, origin, summary, undone FROM audit_changes ORDER BY id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	for rows.Next() {
+		var c Change
+		var at string
+		if err := rows.Scan(&c.ID, &at, &c.Origin, &c.Summary, &c.Undone); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		c.At, _ = time.Parse(time.RFC3339, at)
+		changes = append(changes, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range changes {
+		if changes[i].Entries, err = getAuditEntries(changes[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+func getAuditEntries(changeID int64) ([]AuditEntry, error) {
+	rows, err := database.Query(
+		`SELECT entity, entity_id, old_value, new_value FROM audit_entries WHERE change_id = ? ORDER BY id`, changeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []AuditEntry
+	for rows.Next() {
+		var e AuditEntry
+		var oldV, newV sql.NullString
+		if err := rows.Scan(&e.Table, &e.RowID, &oldV, &newV); err != nil {
+			return nil, err
+		}
+		if e.Old, err = unmarshalRow(oldV); err != nil {
+			return nil, err
+		}
+		if e.New, err = unmarshalRow(newV); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// Undo reverts the latest change that is not undone yet and returns it, or
+// nil when there is nothing to undo.
+func Undo() (*Change, error) {
+	return replay(`SELECT id FROM audit_changes WHERE undone = 0 ORDER BY id DESC LIMIT 1`, true)
+}
+
+// Redo re-applies the change undone most recently, as long as no new change
+// was made since, and returns it, or nil when there is nothing to redo.
+// Undo works newest first, so that is the oldest undone change that was not
+// discarded.
+func Redo() (*Change, error) {
+	return replay(`SELECT id FROM audit_changes WHERE undone = 1 AND discarded = 0 ORDER BY id LIMIT 1`, false)
+}
+
+// replay restores the rows of the change picked by query to their state
+// before it (undo) or after it (redo).
+func replay(query string, undo bool) (*Change, error) {
+	var id int64
+	err := database.QueryRow(query).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var c Change
+	var at string
+	if err := database.QueryRow(`SELECT id, at, origin, summary FROM audit_changes WHERE id = ?`, id).
+		Scan(&c.ID, &at, &c.Origin, &c.Summary); err != nil {
+		return nil, err
+	}
+	c.At, _ = time.Parse(time.RFC3339, at)
+	if c.Entries, err = getAuditEntries(id); err != nil {
+		return nil, err
+	}
+
+	entries := slices.Clone(c.Entries)
+	if undo {
+		slices.Reverse(entries)
+	}
+
+	tx, err := database.Begin()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if err := restoreRow(tx, e, undo); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("%s %d: %w", e.Table, e.RowID, err)
+		}
+	}
+	if _, err := tx.Exec(`UPDATE audit_changes SET undone = ? WHERE id = ?`, undo, id); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	c.Undone = undo
+	return &c, tx.Commit()
+}
+
+// restoreRow puts the row of e back in its state before the change (undo)
+// or after it (redo), deleting it when that state is nil. Rows are updated in
+// place rather than replaced so the search index triggers see the change. A
+// missing row is only inserted again when the change itself created or
+// removed it; otherwise it was purged since and the change cannot be
+// replayed.
+func restoreRow(tx *sql.Tx, e AuditEntry, undo bool) error {
+	table, id := e.Table, e.RowID
+	if !slices.Contains(auditedTables, table) {
+		return fmt.Errorf("table %q is not audited", table)
+	}
+	state, other := e.New, e.Old
+	if undo {
+		state, other = e.Old, e.New
+	}
+	if state == nil {
+		_, err := tx.Exec(`DELETE FROM `+table+` WHERE rowid = ?`, id)
+		return err
+	}
+
+	var n int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM `+table+` WHERE rowid = ?`, id).Scan(&n); err != nil {
+		return err
+	}
+	if n == 0 && other != nil {
+		return fmt.Errorf("the row no longer exists")
+	}
+
+	cols := sortedColumns(state)
+	args := make([]any, 0, len(cols)+1)
+	quoted := make([]string, len(cols))
+	for i, col := range cols {
+		quoted[i] = `"` + strings.ReplaceAll(col, `"`, `""`) + `"`
+		args = append(args, state[col])
+	}
+
+	if n == 0 {
+		// Tables without an id column get the logged rowid back explicitly.
+		if _, ok := state["id"]; !ok {
+			quoted = append(quoted, "rowid")
+			args = append(args, id)
+		}
+		_, err := tx.Exec(`INSERT INTO `+table+` (`+strings.Join(quoted, ", ")+`) VALUES (`+
+			strings.TrimSuffix(strings.Repeat("?, ", len(quoted)), ", ")+`)`, args...)
+		return err
+	}
+	set := make([]string, len(cols))
+	for i, q := range quoted {
+		set[i] = q + " = ?"
+	}
+	args = append(args, id)
+	_, err := tx.Exec(`UPDATE `+table+` SET `+strings.Join(set, ", ")+` WHERE rowid = ?`, args...)
+	return err
+}
@@ -0,0 +1,217 @@
+package db
+
+import (
+	"fmt"
+	"testing"
+)
+
+func mustUndo(t *testing.T) *Change {
+	t.Helper()
+	c, err := Undo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func mustRedo(t *testing.T) *Change {
+	t.Helper()
+	c, err := Redo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func onlyTransaction(t *testing.T) *Transaction {
+	t.Helper()
+	txs, err := GetTransactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	switch len(txs) {
+	case 0:
+		return nil
+	case 1:
+		return &txs[0]
+	}
+	t.Fatalf("%d transactions stored, want at most one", len(txs))
+	return nil
+}
+
+func TestUndoRedoTransactionEdits(t *testing.T) {
+	resetDB(t)
+	mustAddTransaction(t, "2025-05-01", "Food", -12)
+	tx := onlyTransaction(t)
+	tx.Amount, tx.Category = -15, "Dining"
+	if err := UpdateTransaction(*tx); err != nil {
+		t.Fatal(err)
+	}
+
+	if c := mustUndo(t); c == nil || c.Summary != fmt.Sprintf("update transaction %d", tx.ID) {
+		t.Fatalf("undo returned %+v, want the update", c)
+	}
+	if got := onlyTransaction(t); got == nil || got.Amount != -12 || got.Category != "Food" {
+		t.Fatalf("after undoing the update got %+v, want the original row", got)
+	}
+	mustUndo(t)
+	if got := onlyTransaction(t); got != nil {
+		t.Fatalf("after undoing the insert got %+v, want no transaction", got)
+	}
+	if c := mustUndo(t); c != nil {
+		t.Fatalf("undo with an empty history returned %+v", c)
+	}
+
+	mustRedo(t)
+	if got := onlyTransaction(t); got == nil || got.Amount != -12 {
+		t.Fatalf("after redoing the insert got %+v", got)
+	}
+	mustRedo(t)
+	if got := onlyTransaction(t); got == nil || got.Amount != -15 || got.Category != "Dining" {
+		t.Fatalf("after redoing the update got %+v", got)
+	}
+	if c := mustRedo(t); c != nil {
+		t.Fatalf("redo with nothing undone returned %+v", c)
+	}
+}
+
+func TestNewChangeDiscardsRedo(t *testing.T) {
+	resetDB(t)
+	mustAddTransaction(t, "2025-05-01", "Food", -1)
+	mustAddTransaction(t, "2025-05-02", "Food", -2)
+	mustUndo(t)
+	mustAddTransaction(t, "2025-05-03", "Food", -3)
+	mustUndo(t)
+
+	// Only the last undo can be redone; the first was superseded by the
+	// third transaction.
+	if c := mustRedo(t); c == nil {
+		t.Fatal("nothing to redo, want the third transaction")
+	}
+	if c := mustRedo(t); c != nil {
+		t.Fatalf("redo re-applied the discarded change %q", c.Summary)
+	}
+	txs, err := GetTransactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("%d transactions stored, want 2", len(txs))
+	}
+	for _, tx := range txs {
+		if tx.Amount == -2 {
+			t.Error("the discarded transaction came back")
+		}
+	}
+}
+
+func TestGroupIsOneUndoStep(t *testing.T) {
+	resetDB(t)
+	defer SetOrigin(SetOrigin(OriginImport))
+	err := Group("import test", func() error {
+		for _, d := range []string{"2025-06-01", "2025-06-02", "2025-06-03"} {
+			if err := InsertTransaction(Transaction{Amount: -1, Category: "Misc", Date: day(d)}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := GetHistory(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].Origin != OriginImport || len(history[0].Entries) != 3 {
+		t.Fatalf("history = %+v, want one import change with three entries", history)
+	}
+	mustUndo(t)
+	if n := countTransactions(t); n != 0 {
+		t.Errorf("%d transactions left after undoing the group, want 0", n)
+	}
+}
+
+func TestUndoDeleteRestoresChildren(t *testing.T) {
+	resetDB(t)
+	if err := InsertHolding(Holding{Name: "Car", Kind: HoldingAsset}); err != nil {
+		t.Fatal(err)
+	}
+	h, err := GetHoldingByName("Car")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		date  string
+		value float64
+	}{{"2025-01-01", 9000}, {"2025-06-01", 8000}} {
+		if err := SetValuation(h.ID, day(v.date), v.value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := DeleteHolding(h.ID); err != nil {
+		t.Fatal(err)
+	}
+	mustUndo(t)
+
+	holdings, err := GetHoldings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(holdings) != 1 || holdings[0].LatestValue != 8000 {
+		t.Fatalf("after undo got %+v, want Car at its latest valuation 8000", holdings)
+	}
+	var n int
+	if err := database.QueryRow(`SELECT COUNT(*) FROM valuations WHERE holding_id = ?`, h.ID).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("%d valuations restored, want 2", n)
+	}
+}
+
+func TestUndoSetting(t *testing.T) {
+	resetDB(t)
+	if err := SetSetting("test.key", "one"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetSetting("test.key", "two"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetUIState("test.ui", "x"); err != nil {
+		t.Fatal(err)
+	}
+
+	// UI state is not logged, so undo goes straight to the setting.
+	mustUndo(t)
+	if v, _ := GetSetting("test.key"); v != "one" {
+		t.Errorf("setting = %q after undo, want %q", v, "one")
+	}
+	if v, _ := GetSetting("test.ui"); v != "x" {
+		t.Errorf("UI state = %q after undo, want it untouched", v)
+	}
+}
+
+func TestSecretSettingIsNotLogged(t *testing.T) {
+	resetDB(t)
+	if err := SetSecretSetting("test.password", "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := GetSetting("test.password"); v != "hunter2" {
+		t.Fatalf("setting = %q, want it stored", v)
+	}
+
+	var n int
+	if err := database.QueryRow(`SELECT COUNT(*) FROM audit_entries
+	WHERE old_value LIKE '%hunter2%' OR new_value LIKE '%hunter2%'`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("the secret appears in %d audit entries", n)
+	}
+	if c := mustUndo(t); c != nil {
+		t.Errorf("undo returned %+v, want nothing logged", c)
+	}
+}
@@ -0,0 +1,143 @@
+package db
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// -------------------- Bulk edits --------------------
+
+// TransferCategory is the category given to transactions that move money
+// between the user's own accounts rather than spend or earn it.
+const TransferCategory = "Transfer"
+
+// notTransfer is the SQL condition that leaves transfers out of income and
+// spending totals.
+const notTransfer = `category IS NOT '` + TransferCategory + `'`
+
+// SetTransactionsCategory moves every transaction in ids to category as a
+// single change.
+func SetTransactionsCategory(ids []int, category string) error {
+	return record(fmt.Sprintf("set category of %d transaction(s) to %s", len(ids), category), func(c *change) error {
+		for _, id := range ids {
+			if _, err := c.exec("transactions", int64(id), `UPDATE transactions SET category = ? WHERE id = ? AND deleted_at IS NULL`, category, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetTransactionsAccount moves every transaction in ids to account as a
+// single change; an empty account clears it.
+func SetTransactionsAccount(ids []int, account string) error {
+	return record(fmt.Sprintf("set account of %d transaction(s) to %q", len(ids), account), func(c *change) error {
+		for _, id := range ids {
+			if _, err := c.exec("transactions", int64(id), `UPDATE transactions SET account = ? WHERE id = ? AND deleted_at IS NULL`, account, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AddTransactionsTag tags every transaction in ids with tag.
+func AddTransactionsTag(ids []int, tag string) error {
+	return editTags(fmt.Sprintf("tag %d transaction(s) with %s", len(ids), tag), ids, tag, func(tags []string, tag string) []string {
+		return append(tags, tag)
+	})
+}
+
+// RemoveTransactionsTag takes tag off every transaction in ids.
+func RemoveTransactionsTag(ids []int, tag string) error {
+	return editTags(fmt.Sprintf("untag %s from %d transaction(s)", tag, len(ids)), ids, tag, func(tags []string, tag string) []string {
+		return slices.DeleteFunc(tags, func(t string) bool { return strings.EqualFold(t, tag) })
+	})
+}
+
+func editTags(summary string, ids []int, tag string, edit func(tags []string, tag string) []string) error {
+	tag = strings.TrimSpace(tag)
+	if tag == "" || strings.Contains(tag, ",") {
+		return fmt.Errorf("invalid tag %q", tag)
+	}
+	return record(summary, func(c *change) error {
+		for _, id := range ids {
+			var raw string
+			err := c.tx.QueryRow(`SELECT tags FROM transactions WHERE id = ? AND deleted_at IS NULL`, id).Scan(&raw)
+			if err != nil {
+				return fmt.Errorf("transaction %d: %w", id, err)
+			}
+			tags := formatTags(edit(ParseTags(raw), tag))
+			if _, err := c.exec("transactions", int64(id), `UPDATE transactions SET tags = ? WHERE id = ?`, tags, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ParseTags splits a comma-separated tag list, dropping blanks and
+// duplicates (ignoring case) and sorting what is left.
+func ParseTags(s string) []string {
+	var tags []string
+	for _, t := range strings.Split(s, ",") {
+		t = strings.TrimSpace(t)
+		if t != "" && !slices.ContainsFunc(tags, func(x string) bool { return strings.EqualFold(x, t) }) {
+			tags = append(tags, t)
+		}
+	}
+	slices.SortFunc(tags, func(a, b string) int { return strings.Compare(strings.ToLower(a), strings.ToLower(b)) })
+	return tags
+}
+
+// formatTags is the stored form of tags.
+func formatTags(tags []string) string {
+	return strings.Join(ParseTags(strings.Join(tags, ",")), ",")
+}
+
+// idChunk keeps IN lists well under SQLite's limit on bound variables.
+const idChunk = 500
+
+// GetTransactionsByIDs returns the live transactions among ids, by id.
+func GetTransactionsByIDs(ids []int) ([]Transaction, error) {
+	sorted := slices.Sorted(slices.Values(ids))
+	var txs []Transaction
+	for chunk := range slices.Chunk(sorted, idChunk) {
+		args := make([]any, len(chunk))
+		for i, id := range chunk {
+			args[i] = id
+		}
+		rows, err := database.Query(`SELECT `+transactionColumns+` FROM transactions WHERE deleted_at IS NULL AND id IN (?`+
+			strings.Repeat(", ?", len(chunk)-1)+`) ORDER BY id`, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			t, err := scanTransaction(rows)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			txs = append(txs, t)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return txs, nil
+}
+
+func DeleteTransactions(ids []int) error {
+	return record(fmt.Sprintf("delete %d transaction(s)", len(ids)), func(c *change) error {
+		now := time.Now().Format(time.RFC3339)
+		for _, id := range ids {
+			if _, err := c.exec("transactions", int64(id), softDelete("transactions"), now, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,35 @@
+package db
+
+import "testing"
+
+func TestGetTransactionsByIDsChunks(t *testing.T) {
+	resetDB(t)
+	err := Group("add test transactions", func() error {
+		for i := 0; i < idChunk*2+1; i++ {
+			if err := InsertTransaction(Transaction{Amount: -1, Category: "Misc", Date: day("2025-01-01")}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, err := GetTransactionIDs(TransactionFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txs, err := GetTransactionsByIDs(ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != len(ids) {
+		t.Fatalf("got %d transactions, want %d", len(txs), len(ids))
+	}
+	for i := 1; i < len(txs); i++ {
+		if txs[i-1].ID >= txs[i].ID {
+			t.Fatalf("transactions out of id order at %d", i)
+		}
+	}
+}
@@ -3,8 +3,14 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"personal-finance-cli/internal/period"
+
 	_ "github.com/mattn/go-sqlite3"
 )
 
@@ -41,12 +47,197 @@ func InitDB() error {
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		category TEXT NOT NULL,
 		amount REAL NOT NULL,
-		period TEXT NOT NULL,
-		UNIQUE(category, period)
+		period TEXT NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS budget_alerts (
+		budget_id INTEGER NOT NULL,
+		period_from TEXT NOT NULL,
+		threshold REAL NOT NULL,
+		fired_at TEXT NOT NULL,
+		PRIMARY KEY (budget_id, period_from, threshold)
+	);
+
+	CREATE TABLE IF NOT EXISTS budget_templates (
+		name TEXT NOT NULL,
+		category TEXT NOT NULL,
+		amount REAL NOT NULL,
+		kind TEXT NOT NULL DEFAULT 'expense',
+		rollover TEXT NOT NULL DEFAULT 'reset',
+		thresholds TEXT NOT NULL DEFAULT '80,100',
+		PRIMARY KEY (name, category)
+	);
+
+	CREATE TABLE IF NOT EXISTS holdings (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT UNIQUE NOT NULL,
+		kind TEXT NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS valuations (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		holding_id INTEGER NOT NULL REFERENCES holdings(id) ON DELETE CASCADE,
+		date TEXT NOT NULL,
+		value REAL NOT NULL,
+		UNIQUE(holding_id, date)
+	);
+
+	CREATE TABLE IF NOT EXISTS recurring (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		description TEXT NOT NULL,
+		category TEXT NOT NULL,
+		amount REAL NOT NULL,
+		frequency TEXT NOT NULL,
+		day INTEGER NOT NULL DEFAULT 0,
+		start_date TEXT NOT NULL,
+		end_date TEXT,
+		paused INTEGER NOT NULL DEFAULT 0
+	);
+
+	CREATE TABLE IF NOT EXISTS recurring_amounts (
+		recurring_id INTEGER NOT NULL REFERENCES recurring(id) ON DELETE CASCADE,
+		from_date TEXT NOT NULL,
+		amount REAL NOT NULL,
+		PRIMARY KEY (recurring_id, from_date)
+	);
+
+	CREATE TABLE IF NOT EXISTS recurring_instances (
+		recurring_id INTEGER NOT NULL REFERENCES recurring(id) ON DELETE CASCADE,
+		date TEXT NOT NULL,
+		transaction_id INTEGER,
+		PRIMARY KEY (recurring_id, date)
+	);
+
+	CREATE TABLE IF NOT EXISTS settings (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS audit_changes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		at TEXT NOT NULL,
+		origin TEXT NOT NULL,
+		summary TEXT NOT NULL,
+		undone INTEGER NOT NULL DEFAULT 0,
+		discarded INTEGER NOT NULL DEFAULT 0
+	);
+
+	CREATE TABLE IF NOT EXISTS audit_entries (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		change_id INTEGER NOT NULL REFERENCES audit_changes(id) ON DELETE CASCADE,
+		entity TEXT NOT NULL,
+		entity_id INTEGER NOT NULL,
+		old_value TEXT,
+		new_value TEXT
 	);
 	`
 
-	_, err = database.Exec(schema)
+	if _, err = database.Exec(schema); err != nil {
+		return err
+	}
+	if err := migrate(); err != nil {
+		return err
+	}
+	if err := initSearch(); err != nil {
+		return err
+	}
+	_, err = PurgeExpiredTrash(time.Now())
+	return err
+}
+
+// migrate adds columns introduced after the original schema to existing
+// databases. New columns must have defaults so old rows stay valid.
+func migrate() error {
+	columns := []struct{ table, name, def string }{
+		{"budgets", "rollover", "TEXT NOT NULL DEFAULT 'reset'"},
+		{"budgets", "start_date", "TEXT"},
+		{"budgets", "thresholds", "TEXT NOT NULL DEFAULT '80,100'"},
+		{"budgets", "kind", "TEXT NOT NULL DEFAULT 'expense'"},
+		{"transactions", "deleted_at", "TEXT"},
+		{"budgets", "deleted_at", "TEXT"},
+		{"transactions", "account", "TEXT NOT NULL DEFAULT ''"},
+		{"transactions", "tags", "TEXT NOT NULL DEFAULT ''"},
+	}
+	for _, c := range columns {
+		if err := addColumn(c.table, c.name, c.def); err != nil {
+			return err
+		}
+	}
+	if err := dropBudgetsUnique(); err != nil {
+		return err
+	}
+	// Only live budgets must be unique, so a trashed budget stays
+	// restorable after its category and period are budgeted again.
+	_, err := database.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS budgets_category_period
+	ON budgets(category, period) WHERE deleted_at IS NULL`)
+	return err
+}
+
+// dropBudgetsUnique rebuilds a budgets table created with the original
+// UNIQUE(category, period) constraint, which SQLite cannot drop in place.
+func dropBudgetsUnique() error {
+	var n int
+	if err := database.QueryRow(`SELECT COUNT(*) FROM sqlite_master
+	WHERE type = 'index' AND tbl_name = 'budgets' AND name LIKE 'sqlite_autoindex_budgets_%'`).Scan(&n); err != nil || n == 0 {
+		return err
+	}
+
+	const columns = `id, category, amount, period, rollover, start_date, thresholds, kind, deleted_at`
+	tx, err := database.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, stmt := range []string{
+		`CREATE TABLE budgets_new (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			category TEXT NOT NULL,
+			amount REAL NOT NULL,
+			period TEXT NOT NULL,
+			rollover TEXT NOT NULL DEFAULT 'reset',
+			start_date TEXT,
+			thresholds TEXT NOT NULL DEFAULT '80,100',
+			kind TEXT NOT NULL DEFAULT 'expense',
+			deleted_at TEXT
+		)`,
+		`INSERT INTO budgets_new (` + columns + `) SELECT ` + columns + ` FROM budgets`,
+		// Keep the id sequence, so ids of purged budgets in the history are
+		// never reused.
+		`DELETE FROM sqlite_sequence WHERE name = 'budgets_new'`,
+		`UPDATE sqlite_sequence SET name = 'budgets_new' WHERE name = 'budgets'`,
+		`DROP TABLE budgets`,
+		`ALTER TABLE budgets_new RENAME TO budgets`,
+	} {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func addColumn(table, name, def string) error {
+	rows, err := database.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var colName, colType string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &colName, &colType, &notNull, &dflt, &pk); err != nil {
+			return err
+		}
+		if colName == name {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = database.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, name, def))
 	return err
 }
 
@@ -56,18 +247,61 @@ type Transaction struct {
 	Description string
 	Category    string
 	Date        time.Time
+	// Account is the user's account the money moved through; empty when
+	// unknown.
+	Account string
+	Tags    []string
+}
+
+// transactionColumns are the columns scanTransaction reads, in order.
+const transactionColumns = `id, amount, description, category, date, account, tags`
+
+func scanTransaction(row interface{ Scan(...any) error }) (Transaction, error) {
+	var t Transaction
+	var dateStr, tags string
+	if err := row.Scan(&t.ID, &t.Amount, &t.Description, &t.Category, &dateStr, &t.Account, &tags); err != nil {
+		return t, err
+	}
+	t.Date, _ = time.Parse("2006-01-02", dateStr)
+	t.Tags = ParseTags(tags)
+	return t, nil
 }
 
 func InsertTransaction(tx Transaction) error {
-	_, err := database.Exec(
-		`INSERT INTO transactions (amount, description, category, date) VALUES (?, ?, ?, ?)`,
-		tx.Amount, tx.Description, tx.Category, tx.Date.Format("2006-01-02"),
-	)
-	return err
+	return record(fmt.Sprintf("add transaction %q", tx.Description), func(c *change) error {
+		_, err := c.exec("transactions", 0,
+			`INSERT INTO transactions (amount, description, category, date, account, tags) VALUES (?, ?, ?, ?, ?, ?)`,
+			tx.Amount, tx.Description, tx.Category, tx.Date.Format("2006-01-02"), tx.Account, formatTags(tx.Tags),
+		)
+		return err
+	})
+}
+
+// CountMatchingTransactions counts stored transactions with the same date,
+// amount in cents and description as tx; importers use it to skip
+// duplicates.
+func CountMatchingTransactions(tx Transaction) (int, error) {
+	var n int
+	err := database.QueryRow(
+		`SELECT COUNT(*) FROM transactions
+		WHERE date = ? AND CAST(ROUND(amount * 100) AS INTEGER) = ? AND description = ? AND deleted_at IS NULL`,
+		tx.Date.Format("2006-01-02"), amountCents(tx.Amount), tx.Description,
+	).Scan(&n)
+	return n, err
+}
+
+// MatchKey is equal for two transactions exactly when
+// CountMatchingTransactions treats them as the same.
+func MatchKey(tx Transaction) string {
+	return fmt.Sprintf("%s|%d|%s", tx.Date.Format("2006-01-02"), amountCents(tx.Amount), tx.Description)
+}
+
+func amountCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
 }
 
 func GetTransactions() ([]Transaction, error) {
-	rows, err := database.Query(`SELECT id, amount, description, category, date FROM transactions ORDER BY date DESC`)
+	rows, err := database.Query(`SELECT ` + transactionColumns + ` FROM transactions WHERE deleted_at IS NULL ORDER BY date DESC`)
 	if err != nil {
 		return nil, err
 	}
@@ -75,72 +309,229 @@ func GetTransactions() ([]Transaction, error) {
 
 	var txs []Transaction
 	for rows.Next() {
-		var t Transaction
-		var dateStr string
-		if err := rows.Scan(&t.ID, &t.Amount, &t.Description, &t.Category, &dateStr); err != nil {
+		t, err := scanTransaction(rows)
+		if err != nil {
 			return nil, err
 		}
-		t.Date, err = time.Parse("2006-01-02", dateStr)
+		txs = append(txs, t)
+	}
+	return txs, nil
+}
+
+// GetRecentTransactions returns the latest limit transactions, newest first.
+func GetRecentTransactions(limit int) ([]Transaction, error) {
+	rows, err := database.Query(`SELECT `+transactionColumns+` FROM transactions WHERE deleted_at IS NULL ORDER BY date DESC, id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var txs []Transaction
+	for rows.Next() {
+		t, err := scanTransaction(rows)
 		if err != nil {
-			t.Date = time.Time{}
+			return nil, err
 		}
 		txs = append(txs, t)
 	}
-	return txs, nil
+	return txs, rows.Err()
 }
 
+// UpdateTransaction rewrites the amount, description, category and date of
+// t. Account and tags are changed with SetTransactionsAccount and the tag
+// functions.
 func UpdateTransaction(t Transaction) error {
-	_, err := database.Exec(
-		`UPDATE transactions SET amount = ?, description = ?, category = ?, date = ? WHERE id = ?`,
-		t.Amount, t.Description, t.Category, t.Date.Format("2006-01-02"), t.ID,
-	)
-	return err
+	return record(fmt.Sprintf("update transaction %d", t.ID), func(c *change) error {
+		_, err := c.exec("transactions", int64(t.ID),
+			`UPDATE transactions SET amount = ?, description = ?, category = ?, date = ? WHERE id = ? AND deleted_at IS NULL`,
+			t.Amount, t.Description, t.Category, t.Date.Format("2006-01-02"), t.ID,
+		)
+		return err
+	})
 }
 
+// DeleteTransaction moves a transaction to the trash.
 func DeleteTransaction(id int) error {
-	_, err := database.Exec(`DELETE FROM transactions WHERE id = ?`, id)
-	return err
+	return record(fmt.Sprintf("delete transaction %d", id), func(c *change) error {
+		_, err := c.exec("transactions", int64(id), softDelete("transactions"), time.Now().Format(time.RFC3339), id)
+		return err
+	})
 }
 
 func GetTransactionByID(id int) (*Transaction, error) {
-	row := database.QueryRow(`SELECT id, amount, description, category, date FROM transactions WHERE id = ?`, id)
+	row := database.QueryRow(`SELECT `+transactionColumns+` FROM transactions WHERE id = ? AND deleted_at IS NULL`, id)
 
-	var t Transaction
-	var dateStr string
-	err := row.Scan(&t.ID, &t.Amount, &t.Description, &t.Category, &dateStr)
+	t, err := scanTransaction(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-
-	t.Date, err = time.Parse("2006-01-02", dateStr)
-	if err != nil {
-		t.Date = time.Time{}
-	}
 	return &t, nil
 }
 
 // -------------------- Budgets --------------------
 
 type Budget struct {
-	ID       int
-	Category string
-	Amount   float64
-	Period   string
+	ID       int     `json:"id"`
+	Category string  `json:"category"`
+	Amount   float64 `json:"amount"`
+	Period   string  `json:"period"`
+	// Kind is what the amount means: a spending limit, an income target or a
+	// savings goal.
+	Kind string `json:"kind"`
+	// Rollover decides what a recurring budget carries into its next period.
+	Rollover string `json:"rollover"`
+	// Start is the first period a recurring budget accumulates rollover from.
+	Start time.Time `json:"start"`
+	// Thresholds are the percentages of the budget that trigger alerts.
+	Thresholds []float64 `json:"thresholds"`
+}
+
+const (
+	KindExpense = "expense"
+	KindIncome  = "income"
+	KindSavings = "savings"
+)
+
+var BudgetKinds = []string{KindExpense, KindIncome, KindSavings}
+
+func ValidateKind(kind string) error {
+	for _, k := range BudgetKinds {
+		if kind == k {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid kind %q: use expense, income or savings", kind)
+}
+
+var DefaultThresholds = []float64{80, 100}
+
+// ParseThresholds reads a comma-separated list of percentages such as "80,100".
+// An empty string yields an empty, non-nil list, which disables alerts.
+func ParseThresholds(s string) ([]float64, error) {
+	out := []float64{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(part), "%"))
+		if part == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil || v <= 0 {
+			return nil, fmt.Errorf("invalid threshold %q: use positive percentages like 80,100", part)
+		}
+		out = append(out, v)
+	}
+	sort.Float64s(out)
+	return out, nil
+}
+
+// FormatThresholds is the stored and displayed form of thresholds, the
+// reverse of ParseThresholds.
+func FormatThresholds(ts []float64) string {
+	parts := make([]string, len(ts))
+	for i, t := range ts {
+		parts[i] = strconv.FormatFloat(t, 'f', -1, 64)
+	}
+	return strings.Join(parts, ",")
+}
+
+const (
+	RolloverReset     = "reset"
+	RolloverUnspent   = "unspent"
+	RolloverOverspend = "overspend"
+	RolloverBoth      = "both"
+)
+
+var RolloverModes = []string{RolloverReset, RolloverUnspent, RolloverOverspend, RolloverBoth}
+
+func ValidateRollover(mode string) error {
+	for _, m := range RolloverModes {
+		if mode == m {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid rollover %q: use reset, unspent, overspend or both", mode)
+}
+
+const budgetColumns = `id, category, amount, period, kind, rollover, COALESCE(start_date, ''), thresholds`
+
+func scanBudget(row interface{ Scan(...any) error }) (Budget, error) {
+	var b Budget
+	var startStr, thresholds string
+	if err := row.Scan(&b.ID, &b.Category, &b.Amount, &b.Period, &b.Kind, &b.Rollover, &startStr, &thresholds); err != nil {
+		return b, err
+	}
+	if startStr != "" {
+		b.Start, _ = time.Parse("2006-01-02", startStr)
+	}
+	b.Thresholds, _ = ParseThresholds(thresholds)
+	return b, nil
+}
+
+func nullableDate(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.Format("2006-01-02")
+}
+
+// ParsePeriod returns the budget's typed period. Rows saved before periods
+// were validated may have an empty period, which has always meant monthly.
+func (b Budget) ParsePeriod() (period.Period, error) {
+	if b.Period == "" {
+		return period.Period{Kind: period.Monthly}, nil
+	}
+	return period.Parse(b.Period)
+}
+
+// normalize validates the period, kind and rollover mode and rewrites the
+// period in canonical form. Rollover only applies to spending limits.
+func (b *Budget) normalize() error {
+	p, err := period.Parse(b.Period)
+	if err != nil {
+		return err
+	}
+	b.Period = p.String()
+	if b.Kind == "" {
+		b.Kind = KindExpense
+	}
+	if err := ValidateKind(b.Kind); err != nil {
+		return err
+	}
+	if b.Rollover == "" {
+		b.Rollover = RolloverReset
+	}
+	if b.Kind != KindExpense && b.Rollover != RolloverReset {
+		return fmt.Errorf("rollover is only supported for expense budgets")
+	}
+	if b.Thresholds == nil {
+		b.Thresholds = DefaultThresholds
+	}
+	return ValidateRollover(b.Rollover)
 }
 
 func InsertBudget(b Budget) error {
-	_, err := database.Exec(
-		`INSERT INTO budgets (category, amount, period) VALUES (?, ?, ?)`,
-		b.Category, b.Amount, b.Period,
-	)
-	return err
+	if err := b.normalize(); err != nil {
+		return err
+	}
+	if b.Start.IsZero() {
+		if p, _ := b.ParsePeriod(); p.Recurring() {
+			b.Start = time.Now()
+		}
+	}
+	return record(fmt.Sprintf("add budget %s %s", b.Category, b.Period), func(c *change) error {
+		_, err := c.exec("budgets", 0,
+			`INSERT INTO budgets (category, amount, period, kind, rollover, start_date, thresholds) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			b.Category, b.Amount, b.Period, b.Kind, b.Rollover, nullableDate(b.Start), FormatThresholds(b.Thresholds),
+		)
+		return err
+	})
 }
 
 func GetBudgets() ([]Budget, error) {
-	rows, err := database.Query(`SELECT id, category, amount, period FROM budgets ORDER BY period DESC`)
+	rows, err := database.Query(`SELECT ` + budgetColumns + ` FROM budgets WHERE deleted_at IS NULL ORDER BY period DESC`)
 	if err != nil {
 		return nil, err
 	}
@@ -148,8 +539,8 @@ func GetBudgets() ([]Budget, error) {
 
 	var budgets []Budget
 	for rows.Next() {
-		var b Budget
-		if err := rows.Scan(&b.ID, &b.Category, &b.Amount, &b.Period); err != nil {
+		b, err := scanBudget(rows)
+		if err != nil {
 			return nil, err
 		}
 		budgets = append(budgets, b)
@@ -158,10 +549,9 @@ func GetBudgets() ([]Budget, error) {
 }
 
 func GetBudgetByID(id int) (*Budget, error) {
-	row := database.QueryRow(`SELECT id, category, amount, period FROM budgets WHERE id = ?`, id)
+	row := database.QueryRow(`SELECT `+budgetColumns+` FROM budgets WHERE id = ? AND deleted_at IS NULL`, id)
 
-	var b Budget
-	err := row.Scan(&b.ID, &b.Category, &b.Amount, &b.Period)
+	b, err := scanBudget(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -172,39 +562,254 @@ func GetBudgetByID(id int) (*Budget, error) {
 }
 
 func UpdateBudget(b Budget) error {
-	_, err := database.Exec(
-		`UPDATE budgets SET category = ?, amount = ?, period = ? WHERE id = ?`,
-		b.Category, b.Amount, b.Period, b.ID,
-	)
-	return err
+	if err := b.normalize(); err != nil {
+		return err
+	}
+	return record(fmt.Sprintf("update budget %d", b.ID), func(c *change) error {
+		_, err := c.exec("budgets", int64(b.ID),
+			`UPDATE budgets SET category = ?, amount = ?, period = ?, kind = ?, rollover = ?, start_date = ?, thresholds = ? WHERE id = ? AND deleted_at IS NULL`,
+			b.Category, b.Amount, b.Period, b.Kind, b.Rollover, nullableDate(b.Start), FormatThresholds(b.Thresholds), b.ID,
+		)
+		return err
+	})
 }
 
+// DeleteBudget moves a budget to the trash.
 func DeleteBudget(id int) error {
-	_, err := database.Exec(`DELETE FROM budgets WHERE id = ?`, id)
-	return err
+	return record(fmt.Sprintf("delete budget %d", id), func(c *change) error {
+		_, err := c.exec("budgets", int64(id), softDelete("budgets"), time.Now().Format(time.RFC3339), id)
+		return err
+	})
 }
 
+// GetBudgetRemaining returns what is left of b in its current period,
+// including any balance carried over by its rollover mode.
 func GetBudgetRemaining(b Budget) (float64, error) {
 	if database == nil {
 		return 0, fmt.Errorf("database not initialized")
 	}
 
-	period := b.Period
-	if period == "monthly" || period == "" {
-		period = time.Now().Format("2006-01")
+	occ, err := GetBudgetOccurrence(b, time.Now())
+	if err != nil {
+		return 0, err
 	}
+	return occ.Balance, nil
+}
 
+// GetCategorySpent sums the expenses (negative amounts) of a category with
+// dates in [start, end), returned as a positive number.
+func GetCategorySpent(category string, start, end time.Time) (float64, error) {
 	query := `
 	SELECT COALESCE(SUM(CASE WHEN amount < 0 THEN -amount ELSE 0 END), 0) 
 	FROM transactions 
-	WHERE category = ? AND strftime('%Y-%m', date) = ?
+	WHERE category = ? AND date >= ? AND date < ? AND deleted_at IS NULL
 	`
 	var expenses float64
-	err := database.QueryRow(query, b.Category, period).Scan(&expenses)
+	err := database.QueryRow(query, category, start.Format("2006-01-02"), end.Format("2006-01-02")).Scan(&expenses)
+	return expenses, err
+}
+
+// GetCategoryIncome sums the income (positive amounts) of a category with
+// dates in [start, end).
+func GetCategoryIncome(category string, start, end time.Time) (float64, error) {
+	var income float64
+	err := database.QueryRow(`
+	SELECT COALESCE(SUM(amount), 0)
+	FROM transactions
+	WHERE category = ? AND amount > 0 AND date >= ? AND date < ? AND deleted_at IS NULL`,
+		category, start.Format("2006-01-02"), end.Format("2006-01-02")).Scan(&income)
+	return income, err
+}
+
+// GetPeriodTotals returns total income and total expenses (as a positive
+// number) across all categories but transfers with dates in [start, end).
+func GetPeriodTotals(start, end time.Time) (income, expenses float64, err error) {
+	err = database.QueryRow(`
+	SELECT COALESCE(SUM(CASE WHEN amount > 0 THEN amount ELSE 0 END), 0),
+		COALESCE(SUM(CASE WHEN amount < 0 THEN -amount ELSE 0 END), 0)
+	FROM transactions
+	WHERE date >= ? AND date < ? AND deleted_at IS NULL AND `+notTransfer,
+		start.Format("2006-01-02"), end.Format("2006-01-02")).Scan(&income, &expenses)
+	return income, expenses, err
+}
+
+// SavingsRate is (income - expenses) / income, or 0 without income.
+func SavingsRate(income, expenses float64) float64 {
+	if income <= 0 {
+		return 0
+	}
+	return (income - expenses) / income
+}
+
+// BudgetStatus is a budget's position within the occurrence of its period
+// that is being reported on. For income targets and savings goals Actual is
+// what was earned or saved and PctUsed is the progress towards the goal.
+type BudgetStatus struct {
+	Budget    Budget  `json:"budget"`
+	From      string  `json:"from"`
+	To        string  `json:"to"`
+	Carried   float64 `json:"carried"`
+	Available float64 `json:"available"`
+	Actual    float64 `json:"actual"`
+	Remaining float64 `json:"remaining"`
+	PctUsed   float64 `json:"pct_used"`
+	DaysLeft  int     `json:"days_left"`
+	Projected float64 `json:"projected"`
+}
+
+// GetBudgetStatuses reports every budget whose period overlaps window.
+// Recurring budgets are evaluated for the occurrence containing now when now
+// falls inside window, otherwise the occurrence at the window's nearest edge.
+// Projected spend extrapolates the daily rate so far to the whole period.
+func GetBudgetStatuses(window period.Period, now time.Time) ([]BudgetStatus, error) {
+	wStart, wEnd := window.Range(now)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	ref := today
+	if ref.Before(wStart) {
+		ref = wStart
+	} else if !ref.Before(wEnd) {
+		ref = wEnd.AddDate(0, 0, -1)
+	}
+
+	budgets, err := GetBudgets()
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+
+	var statuses []BudgetStatus
+	for _, b := range budgets {
+		p, err := b.ParsePeriod()
+		if err != nil {
+			return nil, fmt.Errorf("budget %d: %w", b.ID, err)
+		}
+		start, end := p.Range(ref)
+		if !period.Overlaps(start, end, wStart, wEnd) {
+			continue
+		}
+
+		occ, err := GetBudgetOccurrence(b, ref)
+		if err != nil {
+			return nil, err
+		}
+		spent := occ.Actual
+
+		totalDays := daysBetween(start, end)
+		elapsed := totalDays
+		switch {
+		case today.Before(start):
+			elapsed = 0
+		case today.Before(end):
+			elapsed = daysBetween(start, today) + 1
+		}
+
+		st := BudgetStatus{
+			Budget:    b,
+			From:      start.Format("2006-01-02"),
+			To:        end.AddDate(0, 0, -1).Format("2006-01-02"),
+			Carried:   occ.CarriedIn,
+			Available: occ.Available,
+			Actual:    spent,
+			Remaining: occ.Balance,
+			DaysLeft:  totalDays - elapsed,
+			Projected: spent,
+		}
+		if occ.Available > 0 {
+			st.PctUsed = spent / occ.Available * 100
+		}
+		if elapsed > 0 {
+			st.Projected = spent / float64(elapsed) * float64(totalDays)
+		}
+		statuses = append(statuses, st)
 	}
+	return statuses, nil
+}
 
-	remaining := b.Amount - expenses
-	return remaining, nil
+// BudgetReport is every budget status for a window plus the window's
+// overall income, expenses and savings rate.
+type BudgetReport struct {
+	From        string         `json:"from"`
+	To          string         `json:"to"`
+	Budgets     []BudgetStatus `json:"budgets"`
+	Income      float64        `json:"income"`
+	Expenses    float64        `json:"expenses"`
+	Saved       float64        `json:"saved"`
+	SavingsRate float64        `json:"savings_rate"`
 }
+
+func GetBudgetReport(window period.Period, now time.Time) (BudgetReport, error) {
+	start, end := window.Range(now)
+	r := BudgetReport{
+		From: start.Format("2006-01-02"),
+		To:   end.AddDate(0, 0, -1).Format("2006-01-02"),
+	}
+
+	var err error
+	if r.Budgets, err = GetBudgetStatuses(window, now); err != nil {
+		return r, err
+	}
+	if r.Income, r.Expenses, err = GetPeriodTotals(start, end); err != nil {
+		return r, err
+	}
+	r.Saved = r.Income - r.Expenses
+	r.SavingsRate = SavingsRate(r.Income, r.Expenses)
+	return r, nil
+}
+
+func daysBetween(start, end time.Time) int {
+	return int(end.Sub(start).Hours() / 24)
+}
+
+// RecordBudgetAlert remembers that a threshold fired for one occurrence of a
+// budget. It returns false when that alert was already recorded, so each
+// threshold is only notified once per period.
+func RecordBudgetAlert(budgetID int, periodFrom string, threshold float64) (bool, error) {
+	res, err := database.Exec(
+		`INSERT OR IGNORE INTO budget_alerts (budget_id, period_from, threshold, fired_at) VALUES (?, ?, ?, ?)`,
+		budgetID, periodFrom, threshold, time.Now().Format(time.RFC3339),
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// -------------------- Settings --------------------
+
+// GetSetting returns the stored value for key, or "" when it was never set.
+func GetSetting(key string) (string, error) {
+	var value string
+	err := database.QueryRow(`SELECT value FROM settings WHERE key = ?`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return value, err
+}
+
+func SetSetting(key, value string) error {
+	return record("set "+key, func(c *change) error {
+		id, err := c.rowid("settings", `key = ?`, key)
+		if err != nil {
+			return err
+		}
+		_, err = c.exec("settings", id, upsertSetting, key, value)
+		return err
+	})
+}
+
+// SetUIState stores UI state such as the last directory or a table's sort
+// order. Unlike SetSetting it is not logged, so undo never touches it.
+func SetUIState(key, value string) error {
+	_, err := database.Exec(upsertSetting, key, value)
+	return err
+}
+
+// SetSecretSetting stores a credential such as a password. It is not logged
+// either, so the value never ends up in the history.
+func SetSecretSetting(key, value string) error {
+	_, err := database.Exec(upsertSetting, key, value)
+	return err
+}
+
+const upsertSetting = `INSERT INTO settings (key, value) VALUES (?, ?)
+	ON CONFLICT(key) DO UPDATE SET value = excluded.value`
@@ -0,0 +1,275 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// In envelope mode every unit of income lands in a "ready to assign" pool and
+// is then assigned to category envelopes. The assignments are the month's
+// expense budgets (period YYYY-MM), so "budget status", alerts and the
+// envelope screen all see the same numbers; assigning, moving and covering
+// adjust those budgets. Balances carry over month to month. An envelope still
+// overspent when its month ends is reset to zero and the deficit is charged
+// to the pool, so Ready to Assign is always the cash on hand minus what sits
+// in envelopes, and overspending the current month lowers it until covered.
+// Transfers between the user's own accounts are neither income nor spending.
+
+const envelopeStartKey = "envelope.start"
+
+// envelopeMonthGlob matches the fixed monthly periods that act as envelope
+// assignments.
+const envelopeMonthGlob = "[0-9][0-9][0-9][0-9]-[0-9][0-9]"
+
+type Envelope struct {
+	Category string  `json:"category"`
+	Assigned float64 `json:"assigned"`
+	Spent    float64 `json:"spent"`
+	Balance  float64 `json:"balance"`
+}
+
+func (e Envelope) Overspent() bool {
+	return e.Balance < -0.005
+}
+
+// EnableEnvelopes starts envelope mode at month (YYYY-MM). Income and
+// expenses before that month are ignored.
+func EnableEnvelopes(month string) error {
+	if _, err := time.Parse("2006-01", month); err != nil {
+		return fmt.Errorf("invalid month %q, expected YYYY-MM", month)
+	}
+	return SetSetting(envelopeStartKey, month)
+}
+
+// EnvelopeStart returns the month envelope mode started, or an error when it
+// was never enabled.
+func EnvelopeStart() (string, error) {
+	start, err := GetSetting(envelopeStartKey)
+	if err != nil {
+		return "", err
+	}
+	if start == "" {
+		return "", fmt.Errorf("envelope mode is not enabled; run \"envelope enable\" first")
+	}
+	return start, nil
+}
+
+// ReadyToAssign is income received from the start month through month minus
+// everything assigned to envelopes over the same span and minus overspending,
+// both charged from earlier months and not yet covered in month.
+func ReadyToAssign(month string) (float64, error) {
+	ready, _, err := envelopeBook(month)
+	return ready, err
+}
+
+// GetEnvelopes lists every envelope with money assigned, a balance carried in
+// or spending since envelope mode started. Categories with spending but
+// nothing assigned show up as overspent so they get covered.
+func GetEnvelopes(month string) ([]Envelope, error) {
+	_, envelopes, err := envelopeBook(month)
+	return envelopes, err
+}
+
+// envelopeBook replays envelope mode month by month up to month and returns
+// the pool and every envelope as of that month.
+func envelopeBook(month string) (float64, []Envelope, error) {
+	start, err := EnvelopeStart()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var income float64
+	err = database.QueryRow(`
+	SELECT COALESCE(SUM(amount), 0) FROM transactions
+	WHERE amount > 0 AND strftime('%Y-%m', date) BETWEEN ? AND ? AND deleted_at IS NULL AND `+notTransfer, start, month).Scan(&income)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// amounts[category][month] holds what was assigned and spent.
+	amounts := map[string]map[string]*Envelope{}
+	add := func(query string, set func(e *Envelope, v float64)) error {
+		rows, err := database.Query(query, start, month)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var cat, m string
+			var v float64
+			if err := rows.Scan(&cat, &m, &v); err != nil {
+				return err
+			}
+			if amounts[cat] == nil {
+				amounts[cat] = map[string]*Envelope{}
+			}
+			if amounts[cat][m] == nil {
+				amounts[cat][m] = &Envelope{Category: cat}
+			}
+			set(amounts[cat][m], v)
+		}
+		return rows.Err()
+	}
+	if err := add(`
+	SELECT category, period, SUM(amount) FROM budgets
+	WHERE kind = 'expense' AND period GLOB '`+envelopeMonthGlob+`' AND period BETWEEN ? AND ? AND deleted_at IS NULL
+	GROUP BY category, period`, func(e *Envelope, v float64) { e.Assigned = v }); err != nil {
+		return 0, nil, err
+	}
+	if err := add(`
+	SELECT category, strftime('%Y-%m', date), SUM(-amount) FROM transactions
+	WHERE amount < 0 AND strftime('%Y-%m', date) BETWEEN ? AND ? AND deleted_at IS NULL AND `+notTransfer+`
+	GROUP BY 1, 2`, func(e *Envelope, v float64) { e.Spent = v }); err != nil {
+		return 0, nil, err
+	}
+
+	months := monthsBetween(start, month)
+	ready := income
+	var envelopes []Envelope
+	for cat, byMonth := range amounts {
+		carry := 0.0
+		for _, m := range months {
+			cur := Envelope{Category: cat}
+			if e := byMonth[m]; e != nil {
+				cur = *e
+			}
+			ready -= cur.Assigned
+			cur.Balance = carry + cur.Assigned - cur.Spent
+			carry = cur.Balance
+			if cur.Overspent() {
+				ready += cur.Balance
+				carry = 0
+			}
+			if m == month {
+				envelopes = append(envelopes, cur)
+			}
+		}
+	}
+	sort.Slice(envelopes, func(i, j int) bool { return envelopes[i].Category < envelopes[j].Category })
+	return ready, envelopes, nil
+}
+
+// monthsBetween lists the months from start through end, both YYYY-MM.
+func monthsBetween(start, end string) []string {
+	from, err1 := time.Parse("2006-01", start)
+	to, err2 := time.Parse("2006-01", end)
+	if err1 != nil || err2 != nil {
+		return nil
+	}
+	var months []string
+	for m := from; !m.After(to); m = m.AddDate(0, 1, 0) {
+		months = append(months, m.Format("2006-01"))
+	}
+	return months
+}
+
+func GetEnvelope(category, month string) (Envelope, error) {
+	envelopes, err := GetEnvelopes(month)
+	if err != nil {
+		return Envelope{}, err
+	}
+	for _, e := range envelopes {
+		if e.Category == category {
+			return e, nil
+		}
+	}
+	return Envelope{Category: category}, nil
+}
+
+// AssignToEnvelope moves amount from the ready-to-assign pool into an
+// envelope. A negative amount returns money from the envelope to the pool.
+func AssignToEnvelope(category, month string, amount float64) error {
+	if amount == 0 {
+		return fmt.Errorf("amount must not be zero")
+	}
+	if amount > 0 {
+		ready, err := ReadyToAssign(month)
+		if err != nil {
+			return err
+		}
+		if amount > ready+0.005 {
+			return fmt.Errorf("only %.2f is ready to assign", math.Max(ready, 0))
+		}
+	} else {
+		e, err := GetEnvelope(category, month)
+		if err != nil {
+			return err
+		}
+		if -amount > e.Balance+0.005 {
+			return fmt.Errorf("envelope %s only holds %.2f", category, math.Max(e.Balance, 0))
+		}
+	}
+
+	summary := fmt.Sprintf("assign %.2f to envelope %s", amount, category)
+	if amount < 0 {
+		summary = fmt.Sprintf("return %.2f from envelope %s", -amount, category)
+	}
+	return record(summary, func(c *change) error {
+		return assign(c, category, month, amount)
+	})
+}
+
+// MoveBetweenEnvelopes shifts amount from one envelope to another. The source
+// must hold at least amount.
+func MoveBetweenEnvelopes(from, to, month string, amount float64) error {
+	if amount <= 0 {
+		return fmt.Errorf("amount must be positive")
+	}
+	if from == to {
+		return fmt.Errorf("cannot move money from an envelope to itself")
+	}
+	src, err := GetEnvelope(from, month)
+	if err != nil {
+		return err
+	}
+	if amount > src.Balance+0.005 {
+		return fmt.Errorf("envelope %s only holds %.2f", from, math.Max(src.Balance, 0))
+	}
+
+	return record(fmt.Sprintf("move %.2f from envelope %s to %s", amount, from, to), func(c *change) error {
+		if err := assign(c, from, month, -amount); err != nil {
+			return err
+		}
+		return assign(c, to, month, amount)
+	})
+}
+
+// assign adds amount to category's budget for month, creating the budget
+// when there is none yet.
+func assign(c *change, category, month string, amount float64) error {
+	var id int64
+	var kind string
+	err := c.tx.QueryRow(`SELECT id, kind FROM budgets WHERE category = ? AND period = ? AND deleted_at IS NULL`,
+		category, month).Scan(&id, &kind)
+	switch {
+	case err == sql.ErrNoRows:
+		_, err = c.exec("budgets", 0,
+			`INSERT INTO budgets (category, amount, period, kind, rollover, thresholds) VALUES (?, ?, ?, ?, ?, ?)`,
+			category, amount, month, KindExpense, RolloverReset, FormatThresholds(DefaultThresholds),
+		)
+		return err
+	case err != nil:
+		return err
+	case kind != KindExpense:
+		return fmt.Errorf("%s has a %s budget for %s; envelopes only use expense budgets", category, kind, month)
+	}
+	_, err = c.exec("budgets", id, `UPDATE budgets SET amount = amount + ? WHERE id = ?`, amount, id)
+	return err
+}
+
+// CoverOverspending moves exactly the overspent amount of category out of
+// the envelope from, bringing category back to zero.
+func CoverOverspending(category, from, month string) (float64, error) {
+	e, err := GetEnvelope(category, month)
+	if err != nil {
+		return 0, err
+	}
+	if !e.Overspent() {
+		return 0, fmt.Errorf("envelope %s is not overspent", category)
+	}
+	deficit := -e.Balance
+	return deficit, MoveBetweenEnvelopes(from, category, month, deficit)
+}
@@ -0,0 +1,258 @@
+package db
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// RecurringItem is a transaction the forecast expects to repeat: either a
+// declared recurring template or a steady charge or income detected in the
+// history.
+type RecurringItem struct {
+	Description string  `json:"description"`
+	Category    string  `json:"category"`
+	Amount      float64 `json:"amount"`
+	Frequency   string  `json:"frequency"`
+	Day         int     `json:"day"`
+	Source      string  `json:"source"`
+	// Next is when a detected item is expected again.
+	Next time.Time `json:"next,omitzero"`
+}
+
+const (
+	SourceDeclared = "declared"
+	SourceDetected = "detected"
+)
+
+// DetectRecurring finds the income and expenses that repeat at a steady
+// amount by the rules of DetectSubscriptions, leaving out the ones a
+// recurring template already covers and the ones that have lapsed.
+func DetectRecurring(now time.Time) ([]RecurringItem, error) {
+	var items []RecurringItem
+	for _, flow := range []struct {
+		cond string
+		sign float64
+	}{{`amount > 0`, 1}, {`amount < 0`, -1}} {
+		subs, err := detectRepeating(flow.cond, now)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range subs {
+			if s.Tracked || s.Lapsed {
+				continue
+			}
+			items = append(items, RecurringItem{
+				Description: s.Description,
+				Category:    s.Category,
+				Amount:      flow.sign * s.LastAmount,
+				Frequency:   s.Frequency,
+				Day:         s.Last.Day(),
+				Source:      SourceDetected,
+				Next:        s.NextExpected,
+			})
+		}
+	}
+	return items, nil
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+type ForecastDay struct {
+	Date     string   `json:"date"`
+	Inflow   float64  `json:"inflow"`
+	Outflow  float64  `json:"outflow"`
+	Balance  float64  `json:"balance"`
+	Negative bool     `json:"negative"`
+	Items    []string `json:"items,omitempty"`
+}
+
+type Forecast struct {
+	From          string          `json:"from"`
+	To            string          `json:"to"`
+	StartBalance  float64         `json:"start_balance"`
+	EndBalance    float64         `json:"end_balance"`
+	LowestBalance float64         `json:"lowest_balance"`
+	FirstNegative string          `json:"first_negative,omitempty"`
+	Recurring     []RecurringItem `json:"recurring"`
+	Budgets       []string        `json:"budgets"`
+	Days          []ForecastDay   `json:"days"`
+}
+
+// budgetSpread is the allowance left in one budget occurrence, spread evenly
+// over its remaining days.
+type budgetSpread struct {
+	end   time.Time
+	daily float64
+}
+
+// scheduleRecurring lists the active declared templates plus the detected
+// items no template covers, and maps each date in [from, to] to the items
+// due on it.
+func scheduleRecurring(now, from, to time.Time) ([]RecurringItem, map[string][]RecurringItem, error) {
+	declared, err := GetRecurring()
+	if err != nil {
+		return nil, nil, err
+	}
+	detected, err := DetectRecurring(now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var items []RecurringItem
+	due := map[string][]RecurringItem{}
+	for _, r := range declared {
+		if r.Paused {
+			continue
+		}
+		item := RecurringItem{Description: r.Description, Category: r.Category, Amount: r.AmountOn(from),
+			Frequency: r.Frequency, Day: r.Day, Source: SourceDeclared}
+		items = append(items, item)
+		for _, d := range r.Occurrences(from, to) {
+			item.Amount = r.AmountOn(d)
+			due[d.Format("2006-01-02")] = append(due[d.Format("2006-01-02")], item)
+		}
+	}
+	for _, r := range detected {
+		items = append(items, r)
+		schedule := Recurring{Frequency: r.Frequency, Day: r.Day, Start: r.Next}
+		for _, d := range schedule.Occurrences(from, to) {
+			due[d.Format("2006-01-02")] = append(due[d.Format("2006-01-02")], r)
+		}
+	}
+	return items, due, nil
+}
+
+// GetForecast projects the balance (the running sum of all transactions)
+// day by day for the given number of months after now. Each day applies the
+// declared and detected recurring items due that day plus an even share of
+// what is left of every expense budget's occurrence, after the recurring
+// items already expected in that budget's category.
+func GetForecast(months int, now time.Time) (Forecast, error) {
+	if months <= 0 {
+		return Forecast{}, fmt.Errorf("months must be positive")
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	first := today.AddDate(0, 0, 1)
+	last := today.AddDate(0, months, 0)
+
+	fc := Forecast{From: first.Format("2006-01-02"), To: last.Format("2006-01-02")}
+	if err := database.QueryRow(
+		`SELECT COALESCE(SUM(amount), 0) FROM transactions WHERE date <= ? AND deleted_at IS NULL`, today.Format("2006-01-02"),
+	).Scan(&fc.StartBalance); err != nil {
+		return fc, err
+	}
+
+	// Budget occurrences can run past the forecast, so schedule a year beyond
+	// it for spreadBudget.
+	recurring, due, err := scheduleRecurring(now, first, last.AddDate(1, 0, 0))
+	if err != nil {
+		return fc, err
+	}
+	fc.Recurring = recurring
+
+	budgets, err := GetBudgets()
+	if err != nil {
+		return fc, err
+	}
+	var expense []Budget
+	for _, b := range budgets {
+		if b.Kind == KindExpense || b.Kind == "" {
+			expense = append(expense, b)
+			fc.Budgets = append(fc.Budgets, fmt.Sprintf("%s (%s)", b.Category, b.Period))
+		}
+	}
+
+	spreads := make([]budgetSpread, len(expense))
+	balance := fc.StartBalance
+	fc.LowestBalance = balance
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		day := ForecastDay{Date: d.Format("2006-01-02")}
+		for _, r := range due[day.Date] {
+			if r.Amount >= 0 {
+				day.Inflow += r.Amount
+			} else {
+				day.Outflow -= r.Amount
+			}
+			day.Items = append(day.Items, fmt.Sprintf("%s %.2f", r.Description, r.Amount))
+		}
+
+		for i, b := range expense {
+			if !d.Before(spreads[i].end) {
+				s, err := spreadBudget(b, d, today, due)
+				if err != nil {
+					return fc, err
+				}
+				spreads[i] = s
+			}
+			day.Outflow += spreads[i].daily
+		}
+
+		balance += day.Inflow - day.Outflow
+		day.Balance = balance
+		if balance < 0 {
+			day.Negative = true
+			if fc.FirstNegative == "" {
+				fc.FirstNegative = day.Date
+			}
+		}
+		fc.LowestBalance = math.Min(fc.LowestBalance, balance)
+		fc.Days = append(fc.Days, day)
+	}
+	fc.EndBalance = balance
+	return fc, nil
+}
+
+// spreadBudget works out the daily allowance of b for the occurrence
+// containing d. The occurrence holding today starts from what is left of it
+// (the same balance GetBudgetRemaining reports, rollover included); later
+// ones start from the full amount. Fixed periods that do not cover d allow
+// nothing.
+func spreadBudget(b Budget, d, today time.Time, due map[string][]RecurringItem) (budgetSpread, error) {
+	p, err := b.ParsePeriod()
+	if err != nil {
+		return budgetSpread{}, err
+	}
+	start, end := p.Range(d)
+	if !p.Recurring() {
+		switch {
+		case d.Before(start):
+			return budgetSpread{end: start}, nil
+		case !d.Before(end):
+			// A past fixed period never applies again.
+			return budgetSpread{end: d.AddDate(100, 0, 0)}, nil
+		}
+	}
+
+	allowance := b.Amount
+	if !today.Before(start) && today.Before(end) {
+		occ, err := GetBudgetOccurrence(b, today)
+		if err != nil {
+			return budgetSpread{}, err
+		}
+		allowance = occ.Balance
+	}
+
+	days := 0
+	for day := d; day.Before(end); day = day.AddDate(0, 0, 1) {
+		days++
+		for _, r := range due[day.Format("2006-01-02")] {
+			if r.Category == b.Category && r.Amount < 0 {
+				allowance += r.Amount
+			}
+		}
+	}
+	if allowance <= 0 || days == 0 {
+		return budgetSpread{end: end}, nil
+	}
+	return budgetSpread{end: end, daily: allowance / float64(days)}, nil
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// TestMain runs the package's tests against a finance.db in a scratch
+// directory, since InitDB always opens the file in the working directory.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "db-test")
+	if err != nil {
+		panic(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		panic(err)
+	}
+	if err := InitDB(); err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	database.Close()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// resetDB empties every table so a test starts from a blank database.
+func resetDB(t *testing.T) {
+	t.Helper()
+	rows, err := database.Query(`SELECT name FROM sqlite_master
+	WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name NOT LIKE 'transactions_fts%'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		tables = append(tables, name)
+	}
+	rows.Close()
+	for _, table := range tables {
+		if _, err := database.Exec(`DELETE FROM ` + table); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func day(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func mustAddTransaction(t *testing.T, date, category string, amount float64) {
+	t.Helper()
+	if err := InsertTransaction(Transaction{Amount: amount, Description: category, Category: category, Date: day(date)}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustAddBudget(t *testing.T, b Budget) Budget {
+	t.Helper()
+	if err := InsertBudget(b); err != nil {
+		t.Fatal(err)
+	}
+	budgets, err := GetBudgets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, got := range budgets {
+		if got.Category == b.Category {
+			return got
+		}
+	}
+	t.Fatalf("budget %s not found after insert", b.Category)
+	return Budget{}
+}
@@ -0,0 +1,187 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// -------------------- Net worth --------------------
+
+// A holding is an asset or liability tracked outside the transactions table
+// (house, car, loan, pension) through dated manual valuations.
+
+const (
+	HoldingAsset     = "asset"
+	HoldingLiability = "liability"
+)
+
+type Holding struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	// LatestValue and LatestDate are filled by GetHoldings.
+	LatestValue float64   `json:"latest_value"`
+	LatestDate  time.Time `json:"latest_date"`
+}
+
+func InsertHolding(h Holding) error {
+	if h.Kind != HoldingAsset && h.Kind != HoldingLiability {
+		return fmt.Errorf("invalid kind %q: use asset or liability", h.Kind)
+	}
+	return record(fmt.Sprintf("add holding %q", h.Name), func(c *change) error {
+		_, err := c.exec("holdings", 0, `INSERT INTO holdings (name, kind) VALUES (?, ?)`, h.Name, h.Kind)
+		return err
+	})
+}
+
+func GetHoldingByName(name string) (*Holding, error) {
+	var h Holding
+	err := database.QueryRow(`SELECT id, name, kind FROM holdings WHERE name = ?`, name).Scan(&h.ID, &h.Name, &h.Kind)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+func GetHoldings() ([]Holding, error) {
+	rows, err := database.Query(`
+	SELECT h.id, h.name, h.kind,
+		COALESCE((SELECT value FROM valuations WHERE holding_id = h.id ORDER BY date DESC LIMIT 1), 0),
+		COALESCE((SELECT MAX(date) FROM valuations WHERE holding_id = h.id), '')
+	FROM holdings h
+	ORDER BY h.kind, h.name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holdings []Holding
+	for rows.Next() {
+		var h Holding
+		var dateStr string
+		if err := rows.Scan(&h.ID, &h.Name, &h.Kind, &h.LatestValue, &dateStr); err != nil {
+			return nil, err
+		}
+		h.LatestDate, _ = time.Parse("2006-01-02", dateStr)
+		holdings = append(holdings, h)
+	}
+	return holdings, rows.Err()
+}
+
+// DeleteHolding removes a holding and its valuations.
+func DeleteHolding(id int) error {
+	return record(fmt.Sprintf("delete holding %d", id), func(c *change) error {
+		if err := c.deleteWhere("valuations", `holding_id = ?`, id); err != nil {
+			return err
+		}
+		_, err := c.exec("holdings", int64(id), `DELETE FROM holdings WHERE id = ?`, id)
+		return err
+	})
+}
+
+// SetValuation records the value of a holding on a date, replacing any
+// valuation already recorded for that day. Liabilities are stored as the
+// positive amount owed.
+func SetValuation(holdingID int, date time.Time, value float64) error {
+	day := date.Format("2006-01-02")
+	return record(fmt.Sprintf("value holding %d on %s", holdingID, day), func(c *change) error {
+		id, err := c.rowid("valuations", `holding_id = ? AND date = ?`, holdingID, day)
+		if err != nil {
+			return err
+		}
+		_, err = c.exec("valuations", id,
+			`INSERT INTO valuations (holding_id, date, value) VALUES (?, ?, ?)
+			ON CONFLICT(holding_id, date) DO UPDATE SET value = excluded.value`,
+			holdingID, day, value,
+		)
+		return err
+	})
+}
+
+// NetWorthPoint is net worth at the end of one interval.
+type NetWorthPoint struct {
+	Date        string             `json:"date"`
+	Cash        float64            `json:"cash"`
+	Assets      float64            `json:"assets"`
+	Liabilities float64            `json:"liabilities"`
+	NetWorth    float64            `json:"net_worth"`
+	Holdings    map[string]float64 `json:"holdings"`
+}
+
+// GetNetWorthSeries evaluates net worth at each of dates. Each holding counts
+// with its latest valuation on or before the date; Cash is the running
+// balance of all transactions up to the date.
+func GetNetWorthSeries(dates []time.Time) ([]NetWorthPoint, error) {
+	holdings, err := GetHoldings()
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]NetWorthPoint, 0, len(dates))
+	for _, d := range dates {
+		day := d.Format("2006-01-02")
+		p := NetWorthPoint{Date: day, Holdings: map[string]float64{}}
+
+		if err := database.QueryRow(
+			`SELECT COALESCE(SUM(amount), 0) FROM transactions WHERE date <= ? AND deleted_at IS NULL`, day,
+		).Scan(&p.Cash); err != nil {
+			return nil, err
+		}
+
+		for _, h := range holdings {
+			var v float64
+			err := database.QueryRow(
+				`SELECT value FROM valuations WHERE holding_id = ? AND date <= ? ORDER BY date DESC LIMIT 1`,
+				h.ID, day,
+			).Scan(&v)
+			if err == sql.ErrNoRows {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			p.Holdings[h.Name] = v
+			if h.Kind == HoldingLiability {
+				p.Liabilities += v
+			} else {
+				p.Assets += v
+			}
+		}
+		p.NetWorth = p.Cash + p.Assets - p.Liabilities
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+// AccountBalance is the running balance of one account's transactions;
+// transactions without an account are grouped under an empty name.
+type AccountBalance struct {
+	Account string  `json:"account"`
+	Balance float64 `json:"balance"`
+}
+
+// GetAccountBalances sums the transactions of each account up to date.
+func GetAccountBalances(date time.Time) ([]AccountBalance, error) {
+	rows, err := database.Query(`SELECT account, SUM(amount) FROM transactions
+	WHERE date <= ? AND deleted_at IS NULL
+	GROUP BY account
+	ORDER BY account = '', account COLLATE NOCASE`, date.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var balances []AccountBalance
+	for rows.Next() {
+		var b AccountBalance
+		if err := rows.Scan(&b.Account, &b.Balance); err != nil {
+			return nil, err
+		}
+		balances = append(balances, b)
+	}
+	return balances, rows.Err()
+}
@@ -0,0 +1,186 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// -------------------- Filtered transaction listings --------------------
+
+// TransactionFilter narrows a transaction listing; zero fields match
+// everything. Amount bounds compare the absolute amount, so "at least 100"
+// finds both large expenses and large income. Text must match every word as
+// in SearchTransactions. Flow keeps only expenses (negative amounts) or only
+// income (positive amounts). Category ignores case unless CategoryExact is
+// set, which matches it the way budgets do.
+type TransactionFilter struct {
+	Category      string    `json:"category,omitempty"`
+	CategoryExact bool      `json:"category_exact,omitempty"`
+	Flow          string    `json:"flow,omitempty"`
+	From          time.Time `json:"from,omitzero"`
+	To            time.Time `json:"to,omitzero"`
+	MinAmount     *float64  `json:"min_amount,omitempty"`
+	MaxAmount     *float64  `json:"max_amount,omitempty"`
+	Text          string    `json:"text,omitempty"`
+}
+
+const (
+	FlowAll      = ""
+	FlowExpenses = "expenses"
+	FlowIncome   = "income"
+)
+
+func (f TransactionFilter) IsZero() bool {
+	return f.Category == "" && f.Flow == "" && f.From.IsZero() && f.To.IsZero() && f.MinAmount == nil && f.MaxAmount == nil &&
+		strings.TrimSpace(f.Text) == ""
+}
+
+func (f TransactionFilter) where() (string, []any) {
+	conds := []string{"deleted_at IS NULL"}
+	var args []any
+	switch {
+	case f.Category == "":
+	case f.CategoryExact:
+		conds = append(conds, `category = ?`)
+		args = append(args, f.Category)
+	default:
+		conds = append(conds, `category = ? COLLATE NOCASE`)
+		args = append(args, f.Category)
+	}
+	switch f.Flow {
+	case FlowExpenses:
+		conds = append(conds, `amount < 0`)
+	case FlowIncome:
+		conds = append(conds, `amount > 0`)
+	}
+	if !f.From.IsZero() {
+		conds = append(conds, `date >= ?`)
+		args = append(args, f.From.Format("2006-01-02"))
+	}
+	if !f.To.IsZero() {
+		conds = append(conds, `date <= ?`)
+		args = append(args, f.To.Format("2006-01-02"))
+	}
+	if f.MinAmount != nil {
+		conds = append(conds, `ABS(amount) >= ?`)
+		args = append(args, *f.MinAmount)
+	}
+	if f.MaxAmount != nil {
+		conds = append(conds, `ABS(amount) <= ?`)
+		args = append(args, *f.MaxAmount)
+	}
+	if words := strings.Fields(f.Text); len(words) > 0 {
+		if ftsEnabled {
+			conds = append(conds, `id IN (SELECT rowid FROM transactions_fts WHERE transactions_fts MATCH ?)`)
+			args = append(args, ftsQuery(words))
+		} else {
+			for _, w := range words {
+				cond, wordArgs := likeWordPrefix(w)
+				conds = append(conds, cond)
+				args = append(args, wordArgs...)
+			}
+		}
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+const (
+	SortID          = "id"
+	SortAmount      = "amount"
+	SortCategory    = "category"
+	SortDate        = "date"
+	SortDescription = "description"
+	SortAccount     = "account"
+)
+
+var SortColumns = []string{SortID, SortAmount, SortCategory, SortDate, SortDescription, SortAccount}
+
+type TransactionSort struct {
+	Column string `json:"column"`
+	Desc   bool   `json:"desc"`
+}
+
+func (s TransactionSort) orderBy() (string, error) {
+	dir := "ASC"
+	if s.Desc {
+		dir = "DESC"
+	}
+	switch s.Column {
+	case SortID:
+		return "id " + dir, nil
+	case SortCategory, SortDescription, SortAccount:
+		return s.Column + " COLLATE NOCASE " + dir + ", id " + dir, nil
+	case SortAmount, SortDate:
+		return s.Column + " " + dir + ", id " + dir, nil
+	}
+	return "", fmt.Errorf("invalid sort column %q", s.Column)
+}
+
+// TransactionTotals summarizes every transaction matching a filter.
+type TransactionTotals struct {
+	Count    int     `json:"count"`
+	Income   float64 `json:"income"`
+	Expenses float64 `json:"expenses"`
+	Net      float64 `json:"net"`
+}
+
+func GetTransactionTotals(f TransactionFilter) (TransactionTotals, error) {
+	where, args := f.where()
+	var t TransactionTotals
+	err := database.QueryRow(`
+	SELECT COUNT(*),
+		COALESCE(SUM(CASE WHEN amount > 0 THEN amount ELSE 0 END), 0),
+		COALESCE(SUM(CASE WHEN amount < 0 THEN -amount ELSE 0 END), 0)
+	FROM transactions WHERE `+where, args...).Scan(&t.Count, &t.Income, &t.Expenses)
+	t.Net = t.Income - t.Expenses
+	return t, err
+}
+
+// QueryTransactions returns one page of the transactions matching f in the
+// order given by s, so listings can load rows as they are scrolled to.
+func QueryTransactions(f TransactionFilter, s TransactionSort, offset, limit int) ([]Transaction, error) {
+	order, err := s.orderBy()
+	if err != nil {
+		return nil, err
+	}
+	where, args := f.where()
+	args = append(args, limit, offset)
+
+	rows, err := database.Query(`SELECT `+transactionColumns+` FROM transactions WHERE `+where+
+		` ORDER BY `+order+` LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var txs []Transaction
+	for rows.Next() {
+		t, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, t)
+	}
+	return txs, rows.Err()
+}
+
+// GetTransactionIDs returns the ids of every transaction matching f.
+func GetTransactionIDs(f TransactionFilter) ([]int, error) {
+	where, args := f.where()
+	rows, err := database.Query(`SELECT id FROM transactions WHERE `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,31 @@
+package db
+
+import "testing"
+
+func TestExactCategoryFilterMatchesBudget(t *testing.T) {
+	resetDB(t)
+	mustAddTransaction(t, "2025-03-03", "Food", -40)
+	mustAddTransaction(t, "2025-03-04", "food", -15)
+	b := mustAddBudget(t, Budget{Category: "Food", Amount: 100, Period: "2025-03"})
+
+	occ, err := GetBudgetOccurrence(b, day("2025-03-10"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := TransactionFilter{Category: "Food", CategoryExact: true, Flow: FlowExpenses, From: day(occ.From), To: day(occ.To)}
+	totals, err := GetTransactionTotals(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totals.Count != 1 || totals.Expenses != occ.Actual {
+		t.Errorf("exact filter found %d rows spending %.2f, want the budget's 1 row and %.2f", totals.Count, totals.Expenses, occ.Actual)
+	}
+
+	f.CategoryExact = false
+	if totals, err = GetTransactionTotals(f); err != nil {
+		t.Fatal(err)
+	}
+	if totals.Count != 2 {
+		t.Errorf("case-insensitive filter found %d rows, want 2", totals.Count)
+	}
+}
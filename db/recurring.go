@@ -0,0 +1,348 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// -------------------- Recurring transactions --------------------
+
+// A recurring template (rent, salary, a subscription) is materialized into
+// real transactions by RunRecurring. Every due date is recorded in
+// recurring_instances, so running it again never creates a second copy.
+
+const (
+	FreqDaily   = "daily"
+	FreqWeekly  = "weekly"
+	FreqMonthly = "monthly"
+	FreqYearly  = "yearly"
+)
+
+var Frequencies = []string{FreqDaily, FreqWeekly, FreqMonthly, FreqYearly}
+
+func ValidateFrequency(freq string) error {
+	for _, f := range Frequencies {
+		if freq == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid frequency %q: use daily, weekly, monthly or yearly", freq)
+}
+
+// AmountChange switches a template to a new amount from a date on, e.g. a
+// rent increase.
+type AmountChange struct {
+	From   time.Time `json:"from"`
+	Amount float64   `json:"amount"`
+}
+
+type Recurring struct {
+	ID          int     `json:"id"`
+	Description string  `json:"description"`
+	Category    string  `json:"category"`
+	Amount      float64 `json:"amount"`
+	Frequency   string  `json:"frequency"`
+	// Day is the day of the month for monthly and yearly templates; days
+	// past the end of a short month fall on its last day.
+	Day     int            `json:"day"`
+	Start   time.Time      `json:"start"`
+	End     time.Time      `json:"end,omitempty"`
+	Paused  bool           `json:"paused"`
+	Changes []AmountChange `json:"changes,omitempty"`
+}
+
+// AmountOn returns the amount in effect on date d.
+func (r Recurring) AmountOn(d time.Time) float64 {
+	amount := r.Amount
+	for _, c := range r.Changes {
+		if !c.From.After(d) {
+			amount = c.Amount
+		}
+	}
+	return amount
+}
+
+// Occurrences lists the due dates of r within [from, to], inclusive,
+// ignoring whether it is paused.
+func (r Recurring) Occurrences(from, to time.Time) []time.Time {
+	if from.Before(r.Start) {
+		from = r.Start
+	}
+	if !r.End.IsZero() && r.End.Before(to) {
+		to = r.End
+	}
+
+	var dates []time.Time
+	add := func(d time.Time) {
+		if !d.Before(from) && !d.After(to) {
+			dates = append(dates, d)
+		}
+	}
+	switch r.Frequency {
+	case FreqDaily:
+		for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+			add(d)
+		}
+	case FreqWeekly:
+		d := r.Start
+		if skip := int(from.Sub(r.Start).Hours() / 24 / 7); skip > 0 {
+			d = d.AddDate(0, 0, 7*skip)
+		}
+		for ; !d.After(to); d = d.AddDate(0, 0, 7) {
+			add(d)
+		}
+	case FreqMonthly:
+		for m := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC); !m.After(to); m = m.AddDate(0, 1, 0) {
+			add(dayOfMonth(m, r.Day))
+		}
+	case FreqYearly:
+		for y := from.Year(); y <= to.Year(); y++ {
+			add(dayOfMonth(time.Date(y, r.Start.Month(), 1, 0, 0, 0, 0, time.UTC), r.Day))
+		}
+	}
+	return dates
+}
+
+// dayOfMonth returns the given day in the month of m, clamped to its last day.
+func dayOfMonth(m time.Time, day int) time.Time {
+	last := time.Date(m.Year(), m.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > last {
+		day = last
+	}
+	return time.Date(m.Year(), m.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+func InsertRecurring(r Recurring) error {
+	if err := ValidateFrequency(r.Frequency); err != nil {
+		return err
+	}
+	if r.Start.IsZero() {
+		r.Start = time.Now()
+	}
+	r.Start = time.Date(r.Start.Year(), r.Start.Month(), r.Start.Day(), 0, 0, 0, 0, time.UTC)
+	if r.Day == 0 {
+		r.Day = r.Start.Day()
+	}
+	if r.Day < 1 || r.Day > 31 {
+		return fmt.Errorf("invalid day %d: use 1-31", r.Day)
+	}
+	if !r.End.IsZero() && r.End.Before(r.Start) {
+		return fmt.Errorf("end date is before start date")
+	}
+
+	return record(fmt.Sprintf("add recurring transaction %q", r.Description), func(c *change) error {
+		_, err := c.exec("recurring", 0,
+			`INSERT INTO recurring (description, category, amount, frequency, day, start_date, end_date) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			r.Description, r.Category, r.Amount, r.Frequency, r.Day, r.Start.Format("2006-01-02"), nullableDate(r.End),
+		)
+		return err
+	})
+}
+
+const recurringColumns = `id, description, category, amount, frequency, day, start_date, COALESCE(end_date, ''), paused`
+
+func scanRecurring(row interface{ Scan(...any) error }) (Recurring, error) {
+	var r Recurring
+	var start, end string
+	if err := row.Scan(&r.ID, &r.Description, &r.Category, &r.Amount, &r.Frequency, &r.Day, &start, &end, &r.Paused); err != nil {
+		return r, err
+	}
+	r.Start, _ = time.Parse("2006-01-02", start)
+	r.End, _ = time.Parse("2006-01-02", end)
+	return r, nil
+}
+
+// GetRecurring returns every template with its amount changes.
+func GetRecurring() ([]Recurring, error) {
+	rows, err := database.Query(`SELECT ` + recurringColumns + ` FROM recurring ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	var list []Recurring
+	for rows.Next() {
+		r, err := scanRecurring(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		list = append(list, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range list {
+		if list[i].Changes, err = getAmountChanges(list[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+func GetRecurringByID(id int) (*Recurring, error) {
+	r, err := scanRecurring(database.QueryRow(`SELECT `+recurringColumns+` FROM recurring WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if r.Changes, err = getAmountChanges(r.ID); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func getAmountChanges(id int) ([]AmountChange, error) {
+	rows, err := database.Query(`SELECT from_date, amount FROM recurring_amounts WHERE recurring_id = ? ORDER BY from_date`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []AmountChange
+	for rows.Next() {
+		var c AmountChange
+		var from string
+		if err := rows.Scan(&from, &c.Amount); err != nil {
+			return nil, err
+		}
+		c.From, _ = time.Parse("2006-01-02", from)
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
+// SetRecurringAmount schedules a new amount from the given date on.
+func SetRecurringAmount(id int, from time.Time, amount float64) error {
+	day := from.Format("2006-01-02")
+	return record(fmt.Sprintf("change amount of recurring transaction %d from %s", id, day), func(c *change) error {
+		rowid, err := c.rowid("recurring_amounts", `recurring_id = ? AND from_date = ?`, id, day)
+		if err != nil {
+			return err
+		}
+		_, err = c.exec("recurring_amounts", rowid,
+			`INSERT INTO recurring_amounts (recurring_id, from_date, amount) VALUES (?, ?, ?)
+			ON CONFLICT(recurring_id, from_date) DO UPDATE SET amount = excluded.amount`,
+			id, day, amount,
+		)
+		return err
+	})
+}
+
+// SetRecurringPaused pauses or resumes a template. Resuming marks the dates
+// that fell due while it was paused as skipped, so they are not back-filled.
+func SetRecurringPaused(id int, paused bool, now time.Time) error {
+	r, err := GetRecurringByID(id)
+	if err != nil {
+		return err
+	}
+	if r == nil {
+		return fmt.Errorf("recurring transaction %d not found", id)
+	}
+
+	summary := fmt.Sprintf("pause recurring transaction %d", id)
+	if !paused {
+		summary = fmt.Sprintf("resume recurring transaction %d", id)
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return record(summary, func(c *change) error {
+		if r.Paused && !paused {
+			for _, d := range r.Occurrences(r.Start, today) {
+				if _, err := c.exec("recurring_instances", 0,
+					`INSERT OR IGNORE INTO recurring_instances (recurring_id, date) VALUES (?, ?)`,
+					id, d.Format("2006-01-02"),
+				); err != nil {
+					return err
+				}
+			}
+		}
+		_, err := c.exec("recurring", int64(id), `UPDATE recurring SET paused = ? WHERE id = ?`, paused, id)
+		return err
+	})
+}
+
+// DeleteRecurring removes a template; transactions it already created stay.
+func DeleteRecurring(id int) error {
+	return record(fmt.Sprintf("delete recurring transaction %d", id), func(c *change) error {
+		for _, child := range []string{"recurring_instances", "recurring_amounts"} {
+			if err := c.deleteWhere(child, `recurring_id = ?`, id); err != nil {
+				return err
+			}
+		}
+		_, err := c.exec("recurring", int64(id), `DELETE FROM recurring WHERE id = ?`, id)
+		return err
+	})
+}
+
+// RunRecurring creates a transaction for every due date of every active
+// template up to now that has not been materialized yet, and returns them.
+// Due dates are UTC midnights, so now counts by its local calendar date.
+func RunRecurring(now time.Time) ([]Transaction, error) {
+	list, err := GetRecurring()
+	if err != nil {
+		return nil, err
+	}
+
+	defer SetOrigin(SetOrigin(OriginRecurring))
+	var created []Transaction
+	err = Group("run recurring transactions", func() error {
+		created, err = runRecurring(list, now)
+		return err
+	})
+	return created, err
+}
+
+func runRecurring(list []Recurring, now time.Time) ([]Transaction, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	var created []Transaction
+	for _, r := range list {
+		if r.Paused {
+			continue
+		}
+		for _, d := range r.Occurrences(r.Start, today) {
+			t := Transaction{Amount: r.AmountOn(d), Description: r.Description, Category: r.Category, Date: d}
+			ok, err := materialize(r.ID, t)
+			if err != nil {
+				return created, err
+			}
+			if ok {
+				created = append(created, t)
+			}
+		}
+	}
+	return created, nil
+}
+
+// materialize inserts t for one due date of a template unless that date was
+// already handled; claiming the instance row first keeps concurrent runs from
+// creating duplicates.
+func materialize(recurringID int, t Transaction) (bool, error) {
+	created := false
+	date := t.Date.Format("2006-01-02")
+	err := record(fmt.Sprintf("add recurring transaction %q", t.Description), func(c *change) error {
+		instance, err := c.exec("recurring_instances", 0,
+			`INSERT OR IGNORE INTO recurring_instances (recurring_id, date) VALUES (?, ?)`, recurringID, date)
+		if err != nil || instance == 0 {
+			return err
+		}
+
+		txID, err := c.exec("transactions", 0,
+			`INSERT INTO transactions (amount, description, category, date) VALUES (?, ?, ?, ?)`,
+			t.Amount, t.Description, t.Category, date,
+		)
+		if err != nil {
+			return err
+		}
+		if _, err := c.exec("recurring_instances", instance,
+			`UPDATE recurring_instances SET transaction_id = ? WHERE rowid = ?`, txID, instance,
+		); err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	return created && err == nil, err
+}
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func mustAddRecurring(t *testing.T, r Recurring) Recurring {
+	t.Helper()
+	if err := InsertRecurring(r); err != nil {
+		t.Fatal(err)
+	}
+	list, err := GetRecurring()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return list[len(list)-1]
+}
+
+func countTransactions(t *testing.T) int {
+	t.Helper()
+	txs, err := GetTransactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(txs)
+}
+
+func TestRunRecurringIsIdempotent(t *testing.T) {
+	resetDB(t)
+	rent := mustAddRecurring(t, Recurring{Description: "Rent", Category: "Rent", Amount: -900, Frequency: FreqMonthly, Start: day("2025-01-01")})
+	if err := SetRecurringAmount(rent.ID, day("2025-03-01"), -950); err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := RunRecurring(day("2025-03-15"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		date   string
+		amount float64
+	}{{"2025-01-01", -900}, {"2025-02-01", -900}, {"2025-03-01", -950}}
+	if len(created) != len(want) {
+		t.Fatalf("created %d transactions, want %d", len(created), len(want))
+	}
+	for i, w := range want {
+		if got := created[i]; got.Date.Format("2006-01-02") != w.date || got.Amount != w.amount {
+			t.Errorf("transaction %d = %s %.2f, want %s %.2f", i, got.Date.Format("2006-01-02"), got.Amount, w.date, w.amount)
+		}
+	}
+
+	again, err := RunRecurring(day("2025-03-31"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again) != 0 {
+		t.Errorf("second run created %d transactions, want none", len(again))
+	}
+	if n := countTransactions(t); n != 3 {
+		t.Errorf("%d transactions stored, want 3", n)
+	}
+}
+
+func TestRunRecurringDoesNotRecreateDeleted(t *testing.T) {
+	resetDB(t)
+	mustAddRecurring(t, Recurring{Description: "Gym", Category: "Health", Amount: -30, Frequency: FreqMonthly, Start: day("2025-01-05")})
+	if _, err := RunRecurring(day("2025-02-10")); err != nil {
+		t.Fatal(err)
+	}
+	txs, err := GetTransactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteTransaction(txs[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := RunRecurring(day("2025-02-10"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 0 {
+		t.Errorf("run after deleting a transaction created %d, want none", len(created))
+	}
+}
+
+func TestRunRecurringSkipsDatesWhilePaused(t *testing.T) {
+	resetDB(t)
+	r := mustAddRecurring(t, Recurring{Description: "Paper", Category: "News", Amount: -5, Frequency: FreqWeekly, Start: day("2025-01-06")})
+	if err := SetRecurringPaused(r.ID, true, day("2025-01-06")); err != nil {
+		t.Fatal(err)
+	}
+	if created, err := RunRecurring(day("2025-01-31")); err != nil || len(created) != 0 {
+		t.Fatalf("paused run created %d (err %v), want none", len(created), err)
+	}
+
+	// Resuming on the 31st skips the four Mondays that passed while paused.
+	if err := SetRecurringPaused(r.ID, false, day("2025-01-31")); err != nil {
+		t.Fatal(err)
+	}
+	created, err := RunRecurring(day("2025-02-10"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 2 || created[0].Date.Format("2006-01-02") != "2025-02-03" {
+		t.Errorf("got %v, want the Mondays 2025-02-03 and 2025-02-10 only", created)
+	}
+}
+
+func TestRunRecurringUsesLocalDate(t *testing.T) {
+	resetDB(t)
+	mustAddRecurring(t, Recurring{Description: "Rent", Category: "Rent", Amount: -900, Frequency: FreqMonthly, Start: day("2026-09-20"), Day: 20})
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	// 21:00 in New York on the 19th is already the 20th in UTC.
+	created, err := RunRecurring(time.Date(2026, 10, 19, 21, 0, 0, 0, ny))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 1 || created[0].Date.Format("2006-01-02") != "2026-09-20" {
+		t.Errorf("got %v, want only the 2026-09-20 rent", created)
+	}
+}
@@ -0,0 +1,379 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"personal-finance-cli/internal/period"
+)
+
+// -------------------- Reports --------------------
+
+const (
+	GroupWeek  = "week"
+	GroupMonth = "month"
+	GroupYear  = "year"
+)
+
+// groupKeyExpr returns the SQL expression bucketing transactions.date by
+// week (labelled with the Monday), month (YYYY-MM) or year (YYYY).
+func groupKeyExpr(groupBy string) (string, error) {
+	switch groupBy {
+	case GroupWeek:
+		return `date(date, 'weekday 0', '-6 days')`, nil
+	case GroupMonth:
+		return `strftime('%Y-%m', date)`, nil
+	case GroupYear:
+		return `strftime('%Y', date)`, nil
+	}
+	return "", fmt.Errorf("invalid group %q: use week, month or year", groupBy)
+}
+
+// CategoryShare is one category's totals within a summary period. Share is
+// the category's percentage of the period's expenses, or of its income for
+// categories that only received money.
+type CategoryShare struct {
+	Category string  `json:"category"`
+	Income   float64 `json:"income"`
+	Expenses float64 `json:"expenses"`
+	Net      float64 `json:"net"`
+	Share    float64 `json:"share_pct"`
+}
+
+type SummaryPeriod struct {
+	Period     string          `json:"period"`
+	Income     float64         `json:"income"`
+	Expenses   float64         `json:"expenses"`
+	Net        float64         `json:"net"`
+	Categories []CategoryShare `json:"categories"`
+}
+
+type Summary struct {
+	From     string          `json:"from"`
+	To       string          `json:"to"`
+	GroupBy  string          `json:"group_by"`
+	Periods  []SummaryPeriod `json:"periods"`
+	Income   float64         `json:"income"`
+	Expenses float64         `json:"expenses"`
+	Net      float64         `json:"net"`
+}
+
+// GetSummary totals income, expenses and net per period between from and to
+// (both inclusive), with a per-category breakdown. Transfers are left out.
+func GetSummary(from, to time.Time, groupBy string) (Summary, error) {
+	s := Summary{From: from.Format("2006-01-02"), To: to.Format("2006-01-02"), GroupBy: groupBy}
+
+	key, err := groupKeyExpr(groupBy)
+	if err != nil {
+		return s, err
+	}
+	rows, err := database.Query(`
+	SELECT `+key+` AS p, COALESCE(category, ''),
+		SUM(CASE WHEN amount > 0 THEN amount ELSE 0 END),
+		SUM(CASE WHEN amount < 0 THEN -amount ELSE 0 END)
+	FROM transactions
+	WHERE date >= ? AND date < ? AND deleted_at IS NULL AND `+notTransfer+`
+	GROUP BY p, category
+	ORDER BY p`,
+		s.From, to.AddDate(0, 0, 1).Format("2006-01-02"))
+	if err != nil {
+		return s, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p string
+		var c CategoryShare
+		if err := rows.Scan(&p, &c.Category, &c.Income, &c.Expenses); err != nil {
+			return s, err
+		}
+		c.Net = c.Income - c.Expenses
+		if len(s.Periods) == 0 || s.Periods[len(s.Periods)-1].Period != p {
+			s.Periods = append(s.Periods, SummaryPeriod{Period: p})
+		}
+		sp := &s.Periods[len(s.Periods)-1]
+		sp.Income += c.Income
+		sp.Expenses += c.Expenses
+		sp.Categories = append(sp.Categories, c)
+	}
+	if err := rows.Err(); err != nil {
+		return s, err
+	}
+
+	for i := range s.Periods {
+		sp := &s.Periods[i]
+		sp.Net = sp.Income - sp.Expenses
+		for j := range sp.Categories {
+			c := &sp.Categories[j]
+			switch {
+			case c.Expenses > 0 && sp.Expenses > 0:
+				c.Share = c.Expenses / sp.Expenses * 100
+			case sp.Income > 0:
+				c.Share = c.Income / sp.Income * 100
+			}
+		}
+		sort.SliceStable(sp.Categories, func(a, b int) bool {
+			return sp.Categories[a].Expenses > sp.Categories[b].Expenses
+		})
+		s.Income += sp.Income
+		s.Expenses += sp.Expenses
+	}
+	s.Net = s.Income - s.Expenses
+	return s, nil
+}
+
+// TrendPoint is one month of a category's spending with its changes against
+// the previous month and the same month a year earlier. Deltas are nil when
+// there is no earlier month to compare with.
+type TrendPoint struct {
+	Month      string   `json:"month"`
+	Amount     float64  `json:"amount"`
+	MoM        *float64 `json:"mom"`
+	MoMPct     *float64 `json:"mom_pct"`
+	YoY        *float64 `json:"yoy"`
+	YoYPct     *float64 `json:"yoy_pct"`
+	RollingAvg float64  `json:"rolling_avg"`
+}
+
+// GetCategoryTrend returns the last months months of spending for category
+// (all categories when empty), ending with the month containing now.
+// Totals are aggregated in SQL; twelve extra months are fetched so the first
+// points have year-over-year and rolling-average history. Year-over-year is
+// left empty when nothing was spent a year earlier.
+func GetCategoryTrend(category string, months, window int, now time.Time) ([]TrendPoint, error) {
+	if months < 1 {
+		return nil, fmt.Errorf("months must be at least 1")
+	}
+	end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, 0)
+	start := end.AddDate(0, -(months + 12), 0)
+
+	query := `
+	SELECT strftime('%Y-%m', date) AS m, SUM(-amount)
+	FROM transactions
+	WHERE amount < 0 AND date >= ? AND date < ? AND deleted_at IS NULL AND ` + notTransfer
+	args := []any{start.Format("2006-01-02"), end.Format("2006-01-02")}
+	if category != "" {
+		query += ` AND category = ?`
+		args = append(args, category)
+	}
+	query += ` GROUP BY m`
+
+	rows, err := database.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := map[string]float64{}
+	for rows.Next() {
+		var m string
+		var v float64
+		if err := rows.Scan(&m, &v); err != nil {
+			return nil, err
+		}
+		totals[m] = v
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var series []float64
+	var labels []string
+	for m := start; m.Before(end); m = m.AddDate(0, 1, 0) {
+		labels = append(labels, m.Format("2006-01"))
+		series = append(series, totals[m.Format("2006-01")])
+	}
+
+	points := make([]TrendPoint, 0, months)
+	for i := 12; i < len(series); i++ {
+		p := TrendPoint{Month: labels[i], Amount: series[i]}
+		p.MoM, p.MoMPct = delta(series[i], series[i-1])
+		if totals[labels[i-12]] != 0 {
+			p.YoY, p.YoYPct = delta(series[i], series[i-12])
+		}
+		sum, n := 0.0, 0
+		for j := i; j > i-window && j >= 0; j-- {
+			sum += series[j]
+			n++
+		}
+		p.RollingAvg = sum / float64(n)
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+func delta(cur, prev float64) (*float64, *float64) {
+	d := cur - prev
+	if prev == 0 {
+		return &d, nil
+	}
+	pct := d / prev * 100
+	return &d, &pct
+}
+
+// VarianceCell is one category's budget against actual spend for a month.
+// Variance is budgeted minus actual, so negative means over budget.
+type VarianceCell struct {
+	Month       string  `json:"month"`
+	Budgeted    float64 `json:"budgeted"`
+	Actual      float64 `json:"actual"`
+	Variance    float64 `json:"variance"`
+	Cumulative  float64 `json:"cumulative_variance"`
+	OverBudget  bool    `json:"over_budget"`
+	HasBudget   bool    `json:"has_budget"`
+	PctOfBudget float64 `json:"pct_of_budget"`
+}
+
+const (
+	TendencyOver  = "chronically over"
+	TendencyUnder = "chronically under"
+)
+
+type CategoryVariance struct {
+	Category string         `json:"category"`
+	Months   []VarianceCell `json:"months"`
+	// Tendency flags categories over budget, or using under 75% of it, in
+	// at least two thirds of their budgeted months (minimum two months).
+	Tendency string `json:"tendency,omitempty"`
+}
+
+type VarianceReport struct {
+	From       string             `json:"from"`
+	To         string             `json:"to"`
+	Categories []CategoryVariance `json:"categories"`
+}
+
+// GetBudgetVariance compares expense budgets with actual spending month by
+// month from the month of from through the month of to. Budgets that do not
+// follow calendar months are prorated: weekly by days/7, quarterly by a
+// third, yearly by a twelfth and custom ranges by overlapping days.
+func GetBudgetVariance(from, to time.Time) (VarianceReport, error) {
+	first := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, 0)
+	r := VarianceReport{From: first.Format("2006-01"), To: end.AddDate(0, -1, 0).Format("2006-01")}
+
+	budgets, err := GetBudgets()
+	if err != nil {
+		return r, err
+	}
+	budgeted := map[string]map[string]float64{}
+	for _, b := range budgets {
+		if b.Kind != KindExpense && b.Kind != "" {
+			continue
+		}
+		p, err := b.ParsePeriod()
+		if err != nil {
+			return r, fmt.Errorf("budget %d: %w", b.ID, err)
+		}
+		for m := first; m.Before(end); m = m.AddDate(0, 1, 0) {
+			me := m.AddDate(0, 1, 0)
+			if p.Recurring() && !b.Start.IsZero() && !b.Start.Before(me) {
+				continue
+			}
+			amt := proratedAmount(b.Amount, p, m, me)
+			if amt == 0 {
+				continue
+			}
+			if budgeted[b.Category] == nil {
+				budgeted[b.Category] = map[string]float64{}
+			}
+			budgeted[b.Category][m.Format("2006-01")] += amt
+		}
+	}
+
+	rows, err := database.Query(`
+	SELECT category, strftime('%Y-%m', date) AS m, SUM(-amount)
+	FROM transactions
+	WHERE amount < 0 AND date >= ? AND date < ? AND deleted_at IS NULL AND `+notTransfer+`
+	GROUP BY category, m`,
+		first.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return r, err
+	}
+	defer rows.Close()
+
+	actual := map[string]map[string]float64{}
+	for rows.Next() {
+		var cat, m string
+		var v float64
+		if err := rows.Scan(&cat, &m, &v); err != nil {
+			return r, err
+		}
+		if budgeted[cat] == nil {
+			continue
+		}
+		if actual[cat] == nil {
+			actual[cat] = map[string]float64{}
+		}
+		actual[cat][m] = v
+	}
+	if err := rows.Err(); err != nil {
+		return r, err
+	}
+
+	categories := make([]string, 0, len(budgeted))
+	for c := range budgeted {
+		categories = append(categories, c)
+	}
+	sort.Strings(categories)
+
+	for _, c := range categories {
+		cv := CategoryVariance{Category: c}
+		cum := 0.0
+		over, under, withBudget := 0, 0, 0
+		for m := first; m.Before(end); m = m.AddDate(0, 1, 0) {
+			key := m.Format("2006-01")
+			cell := VarianceCell{Month: key, Budgeted: budgeted[c][key], Actual: actual[c][key]}
+			cell.HasBudget = cell.Budgeted > 0
+			cell.Variance = cell.Budgeted - cell.Actual
+			cum += cell.Variance
+			cell.Cumulative = cum
+			cell.OverBudget = cell.Variance < 0
+			if cell.HasBudget {
+				cell.PctOfBudget = cell.Actual / cell.Budgeted * 100
+				withBudget++
+				if cell.OverBudget {
+					over++
+				} else if cell.PctOfBudget < 75 {
+					under++
+				}
+			}
+			cv.Months = append(cv.Months, cell)
+		}
+		if withBudget >= 2 {
+			switch {
+			case float64(over) >= float64(withBudget)*2/3:
+				cv.Tendency = TendencyOver
+			case float64(under) >= float64(withBudget)*2/3:
+				cv.Tendency = TendencyUnder
+			}
+		}
+		r.Categories = append(r.Categories, cv)
+	}
+	return r, nil
+}
+
+func proratedAmount(amount float64, p period.Period, monthStart, monthEnd time.Time) float64 {
+	switch p.Kind {
+	case period.Monthly:
+		return amount
+	case period.Weekly:
+		return amount * float64(daysBetween(monthStart, monthEnd)) / 7
+	case period.Quarterly:
+		return amount / 3
+	case period.Yearly:
+		return amount / 12
+	}
+	if !period.Overlaps(p.Start, p.End, monthStart, monthEnd) {
+		return 0
+	}
+	start, end := p.Start, p.End
+	if monthStart.After(start) {
+		start = monthStart
+	}
+	if monthEnd.Before(end) {
+		end = monthEnd
+	}
+	return amount * float64(daysBetween(start, end)) / float64(daysBetween(p.Start, p.End))
+}
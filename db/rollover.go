@@ -0,0 +1,97 @@
+package db
+
+import "time"
+
+// BudgetOccurrence is one materialized period of a budget, with whatever
+// balance was carried into it from the previous period. Actual is the amount
+// spent, earned or saved depending on the budget's kind.
+type BudgetOccurrence struct {
+	From      string  `json:"from"`
+	To        string  `json:"to"`
+	Limit     float64 `json:"limit"`
+	CarriedIn float64 `json:"carried_in"`
+	Available float64 `json:"available"`
+	Actual    float64 `json:"actual"`
+	Balance   float64 `json:"balance"`
+}
+
+// GetBudgetHistory materializes a budget period by period, from its start
+// date up to the occurrence containing until. Fixed periods and budgets
+// without a start date yield a single occurrence.
+func GetBudgetHistory(b Budget, until time.Time) ([]BudgetOccurrence, error) {
+	p, err := b.ParsePeriod()
+	if err != nil {
+		return nil, err
+	}
+	// Periods are UTC dates, so until counts by its local calendar date.
+	until = time.Date(until.Year(), until.Month(), until.Day(), 0, 0, 0, 0, time.UTC)
+
+	first := until
+	if p.Recurring() && !b.Start.IsZero() && b.Start.Before(until) {
+		first = b.Start
+	}
+
+	var history []BudgetOccurrence
+	carry := 0.0
+	start, end := p.Range(first)
+	for {
+		actual, err := budgetActual(b, start, end)
+		if err != nil {
+			return nil, err
+		}
+		occ := BudgetOccurrence{
+			From:      start.Format("2006-01-02"),
+			To:        end.AddDate(0, 0, -1).Format("2006-01-02"),
+			Limit:     b.Amount,
+			CarriedIn: carry,
+			Available: b.Amount + carry,
+			Actual:    actual,
+		}
+		occ.Balance = occ.Available - actual
+		history = append(history, occ)
+
+		if !p.Recurring() || until.Before(end) {
+			return history, nil
+		}
+		carry = carryOver(b.Rollover, occ.Balance)
+		start, end = p.Range(end)
+	}
+}
+
+// GetBudgetOccurrence returns the occurrence of b containing at, including
+// any rollover accumulated since the budget's start.
+func GetBudgetOccurrence(b Budget, at time.Time) (BudgetOccurrence, error) {
+	history, err := GetBudgetHistory(b, at)
+	if err != nil {
+		return BudgetOccurrence{}, err
+	}
+	return history[len(history)-1], nil
+}
+
+// budgetActual measures b over [start, end): spending in its category for
+// expense limits, income in its category for income targets and net savings
+// across all categories for savings goals.
+func budgetActual(b Budget, start, end time.Time) (float64, error) {
+	switch b.Kind {
+	case KindIncome:
+		return GetCategoryIncome(b.Category, start, end)
+	case KindSavings:
+		income, expenses, err := GetPeriodTotals(start, end)
+		return income - expenses, err
+	default:
+		return GetCategorySpent(b.Category, start, end)
+	}
+}
+
+func carryOver(mode string, balance float64) float64 {
+	switch mode {
+	case RolloverUnspent:
+		return max(balance, 0)
+	case RolloverOverspend:
+		return min(balance, 0)
+	case RolloverBoth:
+		return balance
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBudgetHistoryRollover(t *testing.T) {
+	// A 100 monthly food budget: 60 spent in January, 150 in February and
+	// nothing in March.
+	tests := []struct {
+		mode      string
+		available []float64
+	}{
+		{RolloverReset, []float64{100, 100, 100}},
+		{RolloverUnspent, []float64{100, 140, 100}},
+		{RolloverOverspend, []float64{100, 100, 50}},
+		{RolloverBoth, []float64{100, 140, 90}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			resetDB(t)
+			mustAddTransaction(t, "2025-01-10", "Food", -60)
+			mustAddTransaction(t, "2025-02-10", "Food", -100)
+			mustAddTransaction(t, "2025-02-20", "Food", -50)
+			b := mustAddBudget(t, Budget{Category: "Food", Amount: 100, Period: "monthly", Rollover: tt.mode, Start: day("2025-01-01")})
+
+			history, err := GetBudgetHistory(b, day("2025-03-15"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(history) != 3 {
+				t.Fatalf("got %d occurrences, want 3", len(history))
+			}
+			actual := []float64{60, 150, 0}
+			for i, occ := range history {
+				if occ.Available != tt.available[i] || occ.Actual != actual[i] {
+					t.Errorf("occurrence %s: available %.2f actual %.2f, want %.2f and %.2f",
+						occ.From, occ.Available, occ.Actual, tt.available[i], actual[i])
+				}
+				if occ.Balance != occ.Available-occ.Actual {
+					t.Errorf("occurrence %s: balance %.2f is not available minus actual", occ.From, occ.Balance)
+				}
+			}
+
+			occ, err := GetBudgetOccurrence(b, day("2025-03-15"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if occ != history[2] {
+				t.Errorf("GetBudgetOccurrence = %+v, want the last history entry %+v", occ, history[2])
+			}
+		})
+	}
+}
+
+func TestBudgetHistoryFixedPeriod(t *testing.T) {
+	resetDB(t)
+	mustAddTransaction(t, "2025-01-31", "Travel", -80)
+	mustAddTransaction(t, "2025-02-01", "Travel", -30)
+	b := mustAddBudget(t, Budget{Category: "Travel", Amount: 50, Period: "2025-02"})
+
+	history, err := GetBudgetHistory(b, day("2025-06-01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("got %d occurrences, want 1", len(history))
+	}
+	occ := history[0]
+	if occ.From != "2025-02-01" || occ.To != "2025-02-28" || occ.Actual != 30 || occ.Balance != 20 {
+		t.Errorf("got %+v, want February with 30 spent and 20 left", occ)
+	}
+}
+
+func TestBudgetOccurrenceUsesLocalDate(t *testing.T) {
+	resetDB(t)
+	b := mustAddBudget(t, Budget{Category: "Food", Amount: 100, Period: "monthly", Start: day("2026-09-01")})
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	// 21:00 in New York on October 31st is already November in UTC.
+	occ, err := GetBudgetOccurrence(b, time.Date(2026, 10, 31, 21, 0, 0, 0, ny))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if occ.From != "2026-10-01" || occ.To != "2026-10-31" {
+		t.Errorf("got %s..%s, want October", occ.From, occ.To)
+	}
+}
@@ -0,0 +1,187 @@
+package db
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// -------------------- Full-text search --------------------
+
+// Transactions are indexed in an FTS5 table kept in sync by triggers. FTS5
+// is only compiled into go-sqlite3 with the sqlite_fts5 build tag; without
+// it search falls back to LIKE matching, and the triggers are dropped so
+// inserts keep working on a database created by an FTS5 build. The index is
+// rebuilt whenever the triggers have to be recreated.
+
+// HighlightStart and HighlightEnd surround matched terms in
+// SearchResult.Highlighted; callers replace them with their own markup.
+const (
+	HighlightStart = "\x02"
+	HighlightEnd   = "\x03"
+)
+
+var ftsEnabled bool
+
+var ftsTriggers = []struct{ name, body string }{
+	{"transactions_fts_ai", `AFTER INSERT ON transactions BEGIN
+		INSERT INTO transactions_fts(rowid, description, category, tags) VALUES (new.id, new.description, new.category, new.tags);
+	END`},
+	{"transactions_fts_ad", `AFTER DELETE ON transactions BEGIN
+		INSERT INTO transactions_fts(transactions_fts, rowid, description, category, tags)
+		VALUES ('delete', old.id, old.description, old.category, old.tags);
+	END`},
+	{"transactions_fts_au", `AFTER UPDATE ON transactions BEGIN
+		INSERT INTO transactions_fts(transactions_fts, rowid, description, category, tags)
+		VALUES ('delete', old.id, old.description, old.category, old.tags);
+		INSERT INTO transactions_fts(rowid, description, category, tags) VALUES (new.id, new.description, new.category, new.tags);
+	END`},
+}
+
+func initSearch() error {
+	if err := database.QueryRow(`SELECT sqlite_compileoption_used('ENABLE_FTS5')`).Scan(&ftsEnabled); err != nil {
+		return err
+	}
+	if !ftsEnabled {
+		for _, t := range ftsTriggers {
+			if _, err := database.Exec(`DROP TRIGGER IF EXISTS ` + t.name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if _, err := database.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS transactions_fts USING fts5(
+		description, category, tags, content='transactions', content_rowid='id')`); err != nil {
+		return err
+	}
+
+	var n int
+	if err := database.QueryRow(
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name = ?`, ftsTriggers[0].name,
+	).Scan(&n); err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+	for _, t := range ftsTriggers {
+		if _, err := database.Exec(`CREATE TRIGGER IF NOT EXISTS ` + t.name + ` ` + t.body); err != nil {
+			return err
+		}
+	}
+	_, err := database.Exec(`INSERT INTO transactions_fts(transactions_fts) VALUES ('rebuild')`)
+	return err
+}
+
+type SearchResult struct {
+	Transaction
+	Highlighted string  `json:"highlighted"`
+	Rank        float64 `json:"rank"`
+}
+
+// SearchTransactions finds transactions whose description, category or tags
+// contain every word of query, as a prefix. With FTS5 results are ranked
+// by relevance, otherwise newest first.
+func SearchTransactions(query string, limit int) ([]SearchResult, error) {
+	words := strings.Fields(query)
+	if len(words) == 0 {
+		return nil, fmt.Errorf("empty search query")
+	}
+	if ftsEnabled {
+		return searchFTS(words, limit)
+	}
+	return searchLike(words, limit)
+}
+
+// ftsQuery turns words into an FTS5 query matching each of them as a prefix.
+func ftsQuery(words []string) string {
+	terms := make([]string, len(words))
+	for i, w := range words {
+		terms[i] = `"` + strings.ReplaceAll(w, `"`, `""`) + `"*`
+	}
+	return strings.Join(terms, " ")
+}
+
+func searchFTS(words []string, limit int) ([]SearchResult, error) {
+	rows, err := database.Query(`
+	SELECT t.id, t.amount, t.description, t.category, t.date, t.account, t.tags,
+		highlight(transactions_fts, 0, ?, ?), bm25(transactions_fts)
+	FROM transactions_fts
+	JOIN transactions t ON t.id = transactions_fts.rowid
+	WHERE transactions_fts MATCH ? AND t.deleted_at IS NULL
+	ORDER BY bm25(transactions_fts), t.date DESC
+	LIMIT ?`, HighlightStart, HighlightEnd, ftsQuery(words), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var r SearchResult
+		var dateStr, tags string
+		if err := rows.Scan(&r.ID, &r.Amount, &r.Description, &r.Category, &dateStr, &r.Account, &tags,
+			&r.Highlighted, &r.Rank); err != nil {
+			return nil, err
+		}
+		r.Date, _ = time.Parse("2006-01-02", dateStr)
+		r.Tags = ParseTags(tags)
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+// likeWordPrefix is the LIKE fallback for one search word: description,
+// category or tags must contain a word starting with w. Words are split on
+// spaces, and on commas between tags; % and _ in w match themselves.
+func likeWordPrefix(w string) (string, []any) {
+	w = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(w)
+	var conds []string
+	var args []any
+	for _, col := range []string{"description", "category", "tags"} {
+		patterns := []string{w + "%", "% " + w + "%"}
+		if col == "tags" {
+			patterns = append(patterns, "%,"+w+"%")
+		}
+		for _, p := range patterns {
+			conds = append(conds, col+` LIKE ? ESCAPE '\'`)
+			args = append(args, p)
+		}
+	}
+	return "(" + strings.Join(conds, " OR ") + ")", args
+}
+
+func searchLike(words []string, limit int) ([]SearchResult, error) {
+	var conds []string
+	var args []any
+	var patterns []string
+	for _, w := range words {
+		cond, wordArgs := likeWordPrefix(w)
+		conds = append(conds, cond)
+		args = append(args, wordArgs...)
+		patterns = append(patterns, regexp.QuoteMeta(w))
+	}
+	args = append(args, limit)
+
+	rows, err := database.Query(`SELECT `+transactionColumns+` FROM transactions WHERE deleted_at IS NULL AND `+
+		strings.Join(conds, " AND ")+` ORDER BY date DESC LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	re := regexp.MustCompile(`(?i)(^|\s)(` + strings.Join(patterns, "|") + `)`)
+	var results []SearchResult
+	for rows.Next() {
+		t, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		r := SearchResult{Transaction: t}
+		r.Highlighted = re.ReplaceAllString(r.Description, "${1}"+HighlightStart+"${2}"+HighlightEnd)
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
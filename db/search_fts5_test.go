@@ -0,0 +1,42 @@
+//go:build sqlite_fts5
+
+package db
+
+import "testing"
+
+func TestSearchFTS(t *testing.T) {
+	if !ftsEnabled {
+		t.Fatal("built with sqlite_fts5 but FTS5 is not available")
+	}
+	seedSearch(t)
+	for _, tc := range searchCases {
+		checkSearch(t, searchFTS, tc.query, tc.want)
+	}
+}
+
+func TestSearchFTSFollowsChanges(t *testing.T) {
+	seedSearch(t)
+	txs, err := QueryTransactions(TransactionFilter{Text: "storage"}, TransactionSort{Column: SortDate}, 0, 10)
+	if err != nil || len(txs) != 1 {
+		t.Fatalf("got %+v (err %v), want the storage unit", txs, err)
+	}
+	id := txs[0].ID
+
+	if err := AddTransactionsTag([]int{id}, "winter"); err != nil {
+		t.Fatal(err)
+	}
+	checkSearch(t, searchFTS, "winter", []string{"Storage unit"})
+
+	tx := txs[0]
+	tx.Description = "Self storage"
+	if err := UpdateTransaction(tx); err != nil {
+		t.Fatal(err)
+	}
+	checkSearch(t, searchFTS, "self", []string{"Self storage"})
+	checkSearch(t, searchFTS, "unit", nil)
+
+	if err := DeleteTransaction(id); err != nil {
+		t.Fatal(err)
+	}
+	checkSearch(t, searchFTS, "winter", nil)
+}
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// seedSearch stores the transactions the search tests look for; the
+// deleted hardware purchase must never be found.
+func seedSearch(t *testing.T) {
+	t.Helper()
+	resetDB(t)
+	for _, tx := range []Transaction{
+		{Amount: -40, Description: "Hardware store Main St", Category: "Home", Date: day("2025-04-02"), Tags: []string{"diy"}},
+		{Amount: -4, Description: "Coffee shop", Category: "Dining", Date: day("2025-04-03"), Tags: []string{"work trip"}},
+		{Amount: -3, Description: "100% juice", Category: "Food", Date: day("2025-04-04")},
+		{Amount: -90, Description: "Storage unit", Category: "Home", Date: day("2025-04-05")},
+		{Amount: -15, Description: "Hardware outlet", Category: "Home", Date: day("2025-04-06")},
+	} {
+		if err := InsertTransaction(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	txs, err := GetTransactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteTransaction(txs[0].ID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// searchCases hold for both the FTS5 index and the LIKE fallback.
+var searchCases = []struct {
+	query string
+	want  []string
+}{
+	{"hard", []string{"Hardware store Main St"}},
+	{"store", []string{"Hardware store Main St"}},
+	{"diy", []string{"Hardware store Main St"}},
+	{"trip", []string{"Coffee shop"}},
+	{"dining cof", []string{"Coffee shop"}},
+	{"100%", []string{"100% juice"}},
+	{"ware", nil},
+}
+
+func checkSearch(t *testing.T, search func(words []string, limit int) ([]SearchResult, error), query string, want []string) {
+	t.Helper()
+	results, err := search(strings.Fields(query), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range results {
+		got = append(got, r.Description)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("search %q = %q, want %q", query, got, want)
+	}
+}
+
+func TestSearchLike(t *testing.T) {
+	seedSearch(t)
+	for _, tc := range searchCases {
+		checkSearch(t, searchLike, tc.query, tc.want)
+	}
+	// LIKE wildcards in the query match only themselves.
+	checkSearch(t, searchLike, "hard%", nil)
+	checkSearch(t, searchLike, "_offee", nil)
+
+	results, err := searchLike([]string{"hard"}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := HighlightStart + "Hard" + HighlightEnd + "ware store Main St"; results[0].Highlighted != want {
+		t.Errorf("highlighted %q, want %q", results[0].Highlighted, want)
+	}
+}
+
+func TestFilterTextMatchesTags(t *testing.T) {
+	seedSearch(t)
+	txs, err := QueryTransactions(TransactionFilter{Text: "work"}, TransactionSort{Column: SortDate}, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 1 || txs[0].Description != "Coffee shop" {
+		t.Errorf("got %+v, want the coffee tagged \"work trip\"", txs)
+	}
+}
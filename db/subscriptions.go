@@ -0,0 +1,228 @@
+package db
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// -------------------- Subscription detection --------------------
+
+// There is no payee column, so charges are grouped by their description
+// with digits and punctuation stripped ("NETFLIX.COM 8213" and
+// "Netflix.com 9912" are the same payee).
+
+var payeeNoise = regexp.MustCompile(`[^a-z ]+`)
+
+func normalizePayee(description string) string {
+	return strings.Join(strings.Fields(payeeNoise.ReplaceAllString(strings.ToLower(description), " ")), " ")
+}
+
+// cadences maps each detectable frequency to its nominal gap in days.
+var cadences = []struct {
+	frequency string
+	days      float64
+}{
+	{FreqWeekly, 7},
+	{FreqMonthly, 30.4},
+	{FreqYearly, 365.25},
+}
+
+const (
+	// cadenceTolerance is how far a gap may stray from the nominal cadence.
+	cadenceTolerance = 0.2
+	// subscriptionMaxSpread is how far any charge may be from the median
+	// amount; bigger swings mean a regular shop, not a subscription.
+	subscriptionMaxSpread = 0.5
+)
+
+type PriceChange struct {
+	Date time.Time `json:"date"`
+	From float64   `json:"from"`
+	To   float64   `json:"to"`
+}
+
+type Subscription struct {
+	Payee        string        `json:"payee"`
+	Description  string        `json:"description"`
+	Category     string        `json:"category"`
+	Frequency    string        `json:"frequency"`
+	Charges      int           `json:"charges"`
+	First        time.Time     `json:"first"`
+	Last         time.Time     `json:"last"`
+	LastAmount   float64       `json:"last_amount"`
+	NextExpected time.Time     `json:"next_expected"`
+	Annualized   float64       `json:"annualized"`
+	PriceChanges []PriceChange `json:"price_changes,omitempty"`
+	// Lapsed is set when the next expected charge is more than one cycle
+	// overdue, e.g. after cancelling.
+	Lapsed bool `json:"lapsed"`
+	// Tracked is set when a recurring template already covers the charge.
+	Tracked bool `json:"tracked"`
+}
+
+// DetectSubscriptions looks for expenses from the same payee that repeat
+// weekly, monthly or yearly at a steady price: at least three charges
+// (two for yearly), most gaps within 20% of the cadence, and few price
+// changes.
+func DetectSubscriptions(now time.Time) ([]Subscription, error) {
+	subs, err := detectRepeating(`amount < 0`, now)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(subs, func(i, j int) bool { return subs[i].Annualized > subs[j].Annualized })
+	return subs, nil
+}
+
+// detectRepeating applies the subscription rules to the transactions whose
+// amount matches cond, so the forecast can find recurring income the same
+// way. Amounts in the result are absolute.
+func detectRepeating(cond string, now time.Time) ([]Subscription, error) {
+	rows, err := database.Query(`SELECT amount, description, category, date FROM transactions WHERE ` + cond +
+		` AND deleted_at IS NULL AND ` + notTransfer + ` ORDER BY date`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type charge struct {
+		amount float64
+		date   time.Time
+	}
+	type group struct {
+		description, category string
+		charges               []charge
+	}
+	groups := map[string]*group{}
+	var order []string
+	for rows.Next() {
+		var amount float64
+		var desc, cat, dateStr string
+		if err := rows.Scan(&amount, &desc, &cat, &dateStr); err != nil {
+			return nil, err
+		}
+		date, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			continue
+		}
+		payee := normalizePayee(desc)
+		if payee == "" {
+			continue
+		}
+		g, ok := groups[payee]
+		if !ok {
+			g = &group{}
+			groups[payee] = g
+			order = append(order, payee)
+		}
+		g.description, g.category = desc, cat
+		g.charges = append(g.charges, charge{amount: math.Abs(amount), date: date})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	templates, err := GetRecurring()
+	if err != nil {
+		return nil, err
+	}
+	tracked := map[string]bool{}
+	for _, r := range templates {
+		tracked[normalizePayee(r.Description)] = true
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	var subs []Subscription
+	for _, payee := range order {
+		g := groups[payee]
+		if len(g.charges) < 2 {
+			continue
+		}
+
+		gaps := make([]float64, 0, len(g.charges)-1)
+		amounts := make([]float64, 0, len(g.charges))
+		for i, c := range g.charges {
+			amounts = append(amounts, c.amount)
+			if i > 0 {
+				gaps = append(gaps, c.date.Sub(g.charges[i-1].date).Hours()/24)
+			}
+		}
+		gap := median(gaps)
+
+		frequency, nominal := "", 0.0
+		for _, c := range cadences {
+			if math.Abs(gap-c.days) <= c.days*cadenceTolerance {
+				frequency, nominal = c.frequency, c.days
+			}
+		}
+		if frequency == "" || (frequency != FreqYearly && len(g.charges) < 3) {
+			continue
+		}
+		regular := 0
+		for _, d := range gaps {
+			if math.Abs(d-nominal) <= nominal*cadenceTolerance {
+				regular++
+			}
+		}
+		if float64(regular) < float64(len(gaps))*0.75 {
+			continue
+		}
+
+		m := median(amounts)
+		steady := true
+		var changes []PriceChange
+		for i, c := range g.charges {
+			if math.Abs(c.amount-m) > m*subscriptionMaxSpread {
+				steady = false
+				break
+			}
+			if i > 0 && math.Abs(c.amount-g.charges[i-1].amount) >= 0.005 {
+				changes = append(changes, PriceChange{Date: c.date, From: g.charges[i-1].amount, To: c.amount})
+			}
+		}
+		if !steady || len(changes) > len(g.charges)/3 {
+			continue
+		}
+
+		last := g.charges[len(g.charges)-1]
+		sub := Subscription{
+			Payee:        payee,
+			Description:  g.description,
+			Category:     g.category,
+			Frequency:    frequency,
+			Charges:      len(g.charges),
+			First:        g.charges[0].date,
+			Last:         last.date,
+			LastAmount:   last.amount,
+			Annualized:   last.amount * 365.25 / nominal,
+			PriceChanges: changes,
+			Tracked:      tracked[payee],
+		}
+		switch frequency {
+		case FreqWeekly:
+			sub.NextExpected = last.date.AddDate(0, 0, 7)
+		case FreqMonthly:
+			sub.NextExpected = dayOfMonth(time.Date(last.date.Year(), last.date.Month()+1, 1, 0, 0, 0, 0, time.UTC), last.date.Day())
+		case FreqYearly:
+			sub.NextExpected = last.date.AddDate(1, 0, 0)
+		}
+		sub.Lapsed = today.Sub(sub.NextExpected).Hours()/24 > nominal
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
+
+// PromoteSubscription creates a recurring template continuing s from its
+// next expected charge at the latest price.
+func PromoteSubscription(s Subscription) error {
+	return InsertRecurring(Recurring{
+		Description: s.Description,
+		Category:    s.Category,
+		Amount:      -s.LastAmount,
+		Frequency:   s.Frequency,
+		Day:         s.Last.Day(),
+		Start:       s.NextExpected,
+	})
+}
@@ -0,0 +1,211 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"personal-finance-cli/internal/period"
+)
+
+// TemplateItem is one category line of a named budget template. Kind,
+// Rollover and Thresholds are left empty for the budget defaults.
+type TemplateItem struct {
+	Category   string    `json:"category"`
+	Amount     float64   `json:"amount"`
+	Kind       string    `json:"kind"`
+	Rollover   string    `json:"rollover"`
+	Thresholds []float64 `json:"thresholds"`
+}
+
+type BudgetTemplate struct {
+	Name  string         `json:"name"`
+	Items []TemplateItem `json:"items"`
+}
+
+// SaveBudgetTemplate stores items under name, replacing any template with
+// the same name.
+func SaveBudgetTemplate(name string, items []TemplateItem) error {
+	return record(fmt.Sprintf("save budget template %q", name), func(c *change) error {
+		if err := c.deleteWhere("budget_templates", `name = ?`, name); err != nil {
+			return err
+		}
+		for _, it := range items {
+			if it.Kind == "" {
+				it.Kind = KindExpense
+			}
+			if it.Rollover == "" {
+				it.Rollover = RolloverReset
+			}
+			if it.Thresholds == nil {
+				it.Thresholds = DefaultThresholds
+			}
+			if _, err := c.exec("budget_templates", 0,
+				`INSERT INTO budget_templates (name, category, amount, kind, rollover, thresholds) VALUES (?, ?, ?, ?, ?, ?)`,
+				name, it.Category, it.Amount, it.Kind, it.Rollover, FormatThresholds(it.Thresholds),
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func GetBudgetTemplates() ([]BudgetTemplate, error) {
+	rows, err := database.Query(`SELECT name, category, amount, kind, rollover, thresholds FROM budget_templates ORDER BY name, category`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []BudgetTemplate
+	for rows.Next() {
+		var name, thresholds string
+		var it TemplateItem
+		if err := rows.Scan(&name, &it.Category, &it.Amount, &it.Kind, &it.Rollover, &thresholds); err != nil {
+			return nil, err
+		}
+		it.Thresholds, _ = ParseThresholds(thresholds)
+		if len(templates) == 0 || templates[len(templates)-1].Name != name {
+			templates = append(templates, BudgetTemplate{Name: name})
+		}
+		last := &templates[len(templates)-1]
+		last.Items = append(last.Items, it)
+	}
+	return templates, rows.Err()
+}
+
+func GetBudgetTemplate(name string) (*BudgetTemplate, error) {
+	templates, err := GetBudgetTemplates()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range templates {
+		if t.Name == name {
+			return &t, nil
+		}
+	}
+	return nil, nil
+}
+
+func DeleteBudgetTemplate(name string) error {
+	return record(fmt.Sprintf("delete budget template %q", name), func(c *change) error {
+		return c.deleteWhere("budget_templates", `name = ?`, name)
+	})
+}
+
+// ApplyBudgetItems creates a budget for every item in the given period,
+// scaling amounts by adjustPct percent. Categories that already have a
+// budget for that period are skipped and returned.
+func ApplyBudgetItems(items []TemplateItem, periodStr string, adjustPct float64) (created int, skipped []string, err error) {
+	p, err := period.Parse(periodStr)
+	if err != nil {
+		return 0, nil, err
+	}
+	periodStr = p.String()
+
+	err = Group("add budgets for "+periodStr, func() error {
+		created, skipped, err = applyBudgetItems(items, periodStr, adjustPct)
+		return err
+	})
+	return created, skipped, err
+}
+
+func applyBudgetItems(items []TemplateItem, periodStr string, adjustPct float64) (created int, skipped []string, err error) {
+	for _, it := range items {
+		b := it.budget(periodStr)
+		b.Amount = AdjustAmount(it.Amount, adjustPct)
+		exists, err := budgetExists(b.Category, periodStr)
+		if err != nil {
+			return created, skipped, err
+		}
+		if exists {
+			skipped = append(skipped, it.Category)
+			continue
+		}
+		if err := InsertBudget(b); err != nil {
+			return created, skipped, fmt.Errorf("%s: %w", it.Category, err)
+		}
+		created++
+	}
+	return created, skipped, nil
+}
+
+func (it TemplateItem) budget(periodStr string) Budget {
+	return Budget{
+		Category:   it.Category,
+		Amount:     it.Amount,
+		Period:     periodStr,
+		Kind:       it.Kind,
+		Rollover:   it.Rollover,
+		Thresholds: it.Thresholds,
+	}
+}
+
+// AdjustAmount scales amount by pct percent, rounded to cents.
+func AdjustAmount(amount, pct float64) float64 {
+	return math.Round(amount*(1+pct/100)*100) / 100
+}
+
+func budgetExists(category, periodStr string) (bool, error) {
+	var id int
+	err := database.QueryRow(`SELECT id FROM budgets WHERE category = ? AND period = ? AND deleted_at IS NULL`, category, periodStr).Scan(&id)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// BudgetItemsForPeriod returns the budgets stored for exactly periodStr as
+// template items.
+func BudgetItemsForPeriod(periodStr string) ([]TemplateItem, error) {
+	rows, err := database.Query(`SELECT category, amount, kind, rollover, thresholds
+	FROM budgets WHERE period = ? AND deleted_at IS NULL ORDER BY category`, periodStr)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []TemplateItem
+	for rows.Next() {
+		var it TemplateItem
+		var thresholds string
+		if err := rows.Scan(&it.Category, &it.Amount, &it.Kind, &it.Rollover, &thresholds); err != nil {
+			return nil, err
+		}
+		it.Thresholds, _ = ParseThresholds(thresholds)
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+// GetAverageMonthlySpend averages expenses per category over the months
+// full calendar months before the month containing before.
+func GetAverageMonthlySpend(months int, before time.Time) ([]TemplateItem, error) {
+	end := time.Date(before.Year(), before.Month(), 1, 0, 0, 0, 0, time.UTC)
+	start := end.AddDate(0, -months, 0)
+
+	rows, err := database.Query(`
+	SELECT category, SUM(-amount) / ?
+	FROM transactions
+	WHERE amount < 0 AND date >= ? AND date < ? AND deleted_at IS NULL AND `+notTransfer+`
+	GROUP BY category
+	ORDER BY category`,
+		float64(months), start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []TemplateItem
+	for rows.Next() {
+		var it TemplateItem
+		if err := rows.Scan(&it.Category, &it.Amount); err != nil {
+			return nil, err
+		}
+		it.Amount = math.Round(it.Amount*100) / 100
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
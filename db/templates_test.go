@@ -0,0 +1,52 @@
+package db
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBudgetItemsKeepKindRolloverAndThresholds(t *testing.T) {
+	resetDB(t)
+	mustAddBudget(t, Budget{Category: "Salary", Amount: 3000, Period: "2026-09", Kind: KindIncome})
+	mustAddBudget(t, Budget{Category: "Food", Amount: 200, Period: "2026-09", Rollover: RolloverUnspent, Thresholds: []float64{50, 90}})
+
+	items, err := BudgetItemsForPeriod("2026-09")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ApplyBudgetItems(items, "2026-10", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveBudgetTemplate("base", items); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := GetBudgetTemplate("base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ApplyBudgetItems(tmpl.Items, "2026-11", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	budgets, err := GetBudgets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, month := range []string{"2026-10", "2026-11"} {
+		for _, want := range []Budget{
+			{Category: "Salary", Kind: KindIncome, Rollover: RolloverReset, Thresholds: DefaultThresholds},
+			{Category: "Food", Kind: KindExpense, Rollover: RolloverUnspent, Thresholds: []float64{50, 90}},
+		} {
+			i := slices.IndexFunc(budgets, func(b Budget) bool { return b.Category == want.Category && b.Period == month })
+			if i < 0 {
+				t.Errorf("no %s budget for %s", want.Category, month)
+				continue
+			}
+			got := budgets[i]
+			if got.Kind != want.Kind || got.Rollover != want.Rollover || !slices.Equal(got.Thresholds, want.Thresholds) {
+				t.Errorf("%s %s = %s/%s/%v, want %s/%s/%v", month, want.Category,
+					got.Kind, got.Rollover, got.Thresholds, want.Kind, want.Rollover, want.Thresholds)
+			}
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// -------------------- Trash --------------------
+
+// Deleting a transaction or budget only stamps deleted_at; every listing,
+// report and total skips such rows. They can be restored from the trash
+// until they are purged, by hand or automatically once they are older than
+// the retention period. Purging is permanent: the purged rows are also
+// dropped from the history, so undo cannot bring them back.
+
+const (
+	TrashTransaction = "transaction"
+	TrashBudget      = "budget"
+
+	trashRetentionKey         = "trash.retention_days"
+	DefaultTrashRetentionDays = 30
+)
+
+// trashTables maps a trash item kind to its table.
+var trashTables = map[string]string{
+	TrashTransaction: "transactions",
+	TrashBudget:      "budgets",
+}
+
+func trashTable(kind string) (string, error) {
+	table, ok := trashTables[kind]
+	if !ok {
+		return "", fmt.Errorf("invalid trash item type %q (want %s or %s)", kind, TrashTransaction, TrashBudget)
+	}
+	return table, nil
+}
+
+// softDelete is the statement that moves row id of table to the trash,
+// taking the deletion time and the id as arguments.
+func softDelete(table string) string {
+	return `UPDATE ` + table + ` SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`
+}
+
+// TrashItem is a deleted transaction or budget.
+type TrashItem struct {
+	Kind      string    `json:"type"`
+	ID        int       `json:"id"`
+	DeletedAt time.Time `json:"deleted_at"`
+	Summary   string    `json:"summary"`
+}
+
+// GetTrash lists everything in the trash, most recently deleted first.
+func GetTrash() ([]TrashItem, error) {
+	rows, err := database.Query(`
+	SELECT 'transaction', id, deleted_at, date || '  ' || printf('%.2f', amount) || '  ' || COALESCE(category, '') || '  ' || COALESCE(description, '')
+	FROM transactions WHERE deleted_at IS NOT NULL
+	UNION ALL
+	SELECT 'budget', id, deleted_at, category || '  ' || period || '  ' || kind || '  ' || printf('%.2f', amount)
+	FROM budgets WHERE deleted_at IS NOT NULL
+	ORDER BY 3 DESC, 2 DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []TrashItem
+	for rows.Next() {
+		var it TrashItem
+		var deletedAt string
+		if err := rows.Scan(&it.Kind, &it.ID, &deletedAt, &it.Summary); err != nil {
+			return nil, err
+		}
+		it.DeletedAt, _ = time.Parse(time.RFC3339, deletedAt)
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+// RestoreFromTrash brings a deleted transaction or budget back. Restoring is
+// logged like any other change, so it can be undone.
+func RestoreFromTrash(kind string, id int) error {
+	table, err := trashTable(kind)
+	if err != nil {
+		return err
+	}
+	if err := inTrash(table, kind, id); err != nil {
+		return err
+	}
+	if kind == TrashBudget {
+		var category, period string
+		var live int
+		if err := database.QueryRow(`SELECT category, period,
+			(SELECT COUNT(*) FROM budgets l WHERE l.category = b.category AND l.period = b.period AND l.deleted_at IS NULL)
+		FROM budgets b WHERE id = ?`, id).Scan(&category, &period, &live); err != nil {
+			return err
+		}
+		if live > 0 {
+			return fmt.Errorf("there is already a %s budget for %s; delete it before restoring budget %d", category, period, id)
+		}
+	}
+	return record(fmt.Sprintf("restore %s %d", kind, id), func(c *change) error {
+		_, err := c.exec(table, int64(id), `UPDATE `+table+` SET deleted_at = NULL WHERE id = ?`, id)
+		return err
+	})
+}
+
+// PurgeFromTrash permanently removes one deleted transaction or budget.
+func PurgeFromTrash(kind string, id int) error {
+	table, err := trashTable(kind)
+	if err != nil {
+		return err
+	}
+	if err := inTrash(table, kind, id); err != nil {
+		return err
+	}
+	return purge(func(tx *sql.Tx) error {
+		_, err := purgeRows(tx, table, `id = ?`, id)
+		return err
+	})
+}
+
+func inTrash(table, kind string, id int) error {
+	var n int
+	if err := database.QueryRow(`SELECT COUNT(*) FROM `+table+` WHERE id = ? AND deleted_at IS NOT NULL`, id).
+		Scan(&n); err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%s %d is not in the trash", kind, id)
+	}
+	return nil
+}
+
+// PurgeTrash permanently removes everything deleted before the given time
+// and returns how many rows went. A zero time empties the whole trash.
+func PurgeTrash(before time.Time) (int, error) {
+	cutoff := "9999"
+	if !before.IsZero() {
+		cutoff = before.Format(time.RFC3339)
+	}
+	total := 0
+	err := purge(func(tx *sql.Tx) error {
+		for _, table := range []string{"transactions", "budgets"} {
+			n, err := purgeRows(tx, table, `deleted_at < ?`, cutoff)
+			if err != nil {
+				return err
+			}
+			total += n
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// purge runs fn in a database transaction and then drops the changes that
+// were left without entries.
+func purge(fn func(tx *sql.Tx) error) error {
+	tx, err := database.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM audit_changes
+	WHERE NOT EXISTS (SELECT 1 FROM audit_entries WHERE change_id = audit_changes.id)`); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// purgeRows deletes the trashed rows of table matching cond together with
+// their audit entries and returns how many rows went.
+func purgeRows(tx *sql.Tx, table, cond string, args ...any) (int, error) {
+	rows, err := tx.Query(`SELECT id FROM `+table+` WHERE deleted_at IS NOT NULL AND `+cond, args...)
+	if err != nil {
+		return 0, err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		if _, err := tx.Exec(`DELETE FROM `+table+` WHERE id = ?`, id); err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec(`DELETE FROM audit_entries WHERE entity = ? AND entity_id = ?`, table, id); err != nil {
+			return 0, err
+		}
+	}
+	return len(ids), nil
+}
+
+// TrashRetentionDays is how long deleted rows stay in the trash; 0 keeps
+// them until they are purged by hand.
+func TrashRetentionDays() (int, error) {
+	raw, err := GetSetting(trashRetentionKey)
+	if err != nil || raw == "" {
+		return DefaultTrashRetentionDays, err
+	}
+	days, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s setting %q", trashRetentionKey, raw)
+	}
+	return days, nil
+}
+
+func SetTrashRetentionDays(days int) error {
+	if days < 0 {
+		return fmt.Errorf("retention must be zero or more days")
+	}
+	return SetSetting(trashRetentionKey, strconv.Itoa(days))
+}
+
+// PurgeExpiredTrash removes rows that have been in the trash longer than the
+// retention period.
+func PurgeExpiredTrash(now time.Time) (int, error) {
+	days, err := TrashRetentionDays()
+	if err != nil || days == 0 {
+		return 0, err
+	}
+	return PurgeTrash(now.AddDate(0, 0, -days))
+}
@@ -0,0 +1,173 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeleteAndRestoreTransaction(t *testing.T) {
+	resetDB(t)
+	mustAddTransaction(t, "2025-07-01", "Food", -20)
+	tx := onlyTransaction(t)
+	if err := DeleteTransaction(tx.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := onlyTransaction(t); got != nil {
+		t.Fatalf("deleted transaction still listed: %+v", got)
+	}
+	_, expenses, err := GetPeriodTotals(day("2025-07-01"), day("2025-08-01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expenses != 0 {
+		t.Errorf("expenses = %.2f with the only transaction in the trash, want 0", expenses)
+	}
+	items, err := GetTrash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Kind != TrashTransaction || items[0].ID != tx.ID {
+		t.Fatalf("trash = %+v, want the deleted transaction", items)
+	}
+
+	if err := RestoreFromTrash(TrashTransaction, tx.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := onlyTransaction(t); got == nil || got.ID != tx.ID {
+		t.Fatalf("restored transaction = %+v", got)
+	}
+	if err := RestoreFromTrash(TrashTransaction, tx.ID); err == nil {
+		t.Error("restoring a transaction that is not in the trash succeeded")
+	}
+}
+
+func TestPurgeExpiredTrash(t *testing.T) {
+	resetDB(t)
+	mustAddTransaction(t, "2025-07-01", "Food", -1)
+	mustAddTransaction(t, "2025-07-02", "Food", -2)
+	txs, err := GetTransactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tx := range txs {
+		if err := DeleteTransaction(tx.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().AddDate(0, 0, -40).Format(time.RFC3339)
+	if _, err := database.Exec(`UPDATE transactions SET deleted_at = ? WHERE amount = -1`, old); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := PurgeExpiredTrash(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("purged %d rows, want the one deleted 40 days ago", n)
+	}
+	items, err := GetTrash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("trash holds %d items after the purge, want 1", len(items))
+	}
+
+	if n, err := PurgeTrash(time.Time{}); err != nil || n != 1 {
+		t.Fatalf("emptying the trash purged %d (err %v), want 1", n, err)
+	}
+}
+
+func TestUndoCannotBringBackPurgedRows(t *testing.T) {
+	resetDB(t)
+	if err := SetSetting("test.key", "before"); err != nil {
+		t.Fatal(err)
+	}
+	mustAddTransaction(t, "2025-07-01", "Food", -5)
+	tx := onlyTransaction(t)
+	if err := DeleteTransaction(tx.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := PurgeFromTrash(TrashTransaction, tx.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := GetHistory(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("history = %+v, want only the setting change", history)
+	}
+	if c := mustUndo(t); c == nil || c.Summary != "set test.key" {
+		t.Fatalf("undo returned %+v, want the setting change", c)
+	}
+	var n int
+	if err := database.QueryRow(`SELECT COUNT(*) FROM transactions`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("%d transactions in the table after undo, want the purged row to stay gone", n)
+	}
+}
+
+func TestUndoFailsWhenRowIsGone(t *testing.T) {
+	resetDB(t)
+	mustAddTransaction(t, "2025-07-01", "Food", -5)
+	tx := onlyTransaction(t)
+	tx.Amount = -6
+	if err := UpdateTransaction(*tx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.Exec(`DELETE FROM transactions WHERE id = ?`, tx.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Undo(); err == nil {
+		t.Fatal("undoing an update of a missing row succeeded")
+	}
+	if n := countTransactions(t); n != 0 {
+		t.Errorf("undo inserted %d transaction(s), want none", n)
+	}
+}
+
+func TestTrashedBudgetSurvivesNewBudget(t *testing.T) {
+	resetDB(t)
+	old := mustAddBudget(t, Budget{Category: "Fuel", Amount: 100, Period: "2025-07"})
+	if err := DeleteBudget(old.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := InsertBudget(Budget{Category: "Fuel", Amount: 120, Period: "2025-07"}); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := GetTrash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].ID != old.ID {
+		t.Fatalf("trash = %+v, want the old budget kept", items)
+	}
+	if err := RestoreFromTrash(TrashBudget, old.ID); err == nil {
+		t.Fatal("restored a budget over a live one for the same category and period")
+	}
+
+	budgets, err := GetBudgets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteBudget(budgets[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := RestoreFromTrash(TrashBudget, old.ID); err != nil {
+		t.Fatal(err)
+	}
+	if budgets, err = GetBudgets(); err != nil {
+		t.Fatal(err)
+	}
+	if len(budgets) != 1 || budgets[0].Amount != 100 {
+		t.Errorf("budgets = %+v, want the restored 100 budget", budgets)
+	}
+}
@@ -0,0 +1,167 @@
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"personal-finance-cli/db"
+)
+
+// Touch is a category/date pair written by an insert, update or import.
+type Touch struct {
+	Category string
+	Date     time.Time
+}
+
+// Alert is the highest threshold a budget has crossed in one period.
+type Alert struct {
+	BudgetID  int     `json:"budget_id"`
+	Category  string  `json:"category"`
+	From      string  `json:"from"`
+	To        string  `json:"to"`
+	Threshold float64 `json:"threshold"`
+	PctUsed   float64 `json:"pct_used"`
+	Spent     float64 `json:"spent"`
+	Available float64 `json:"available"`
+	// New is set the first time this threshold fires for the period; only
+	// new alerts are sent to notifiers.
+	New bool `json:"new"`
+}
+
+func (a Alert) Message() string {
+	return fmt.Sprintf("Budget %s (%s to %s) is at %.0f%% (%.2f of %.2f), past the %.0f%% threshold",
+		a.Category, a.From, a.To, a.PctUsed, a.Spent, a.Available, a.Threshold)
+}
+
+// Evaluate checks every budget affected by touched against its thresholds,
+// records newly crossed ones and dispatches them to the configured
+// notifiers. Notifier failures are returned alongside the alerts.
+func Evaluate(touched ...Touch) ([]Alert, error) {
+	if len(touched) == 0 {
+		return nil, nil
+	}
+	budgets, err := db.GetBudgets()
+	if err != nil {
+		return nil, err
+	}
+
+	// Many touches usually fall in the same few budget periods, and checking
+	// one replays the budget's history, so each period is checked once.
+	byCategory := map[string][]db.Budget{}
+	for _, b := range budgets {
+		if b.Kind == db.KindExpense {
+			byCategory[b.Category] = append(byCategory[b.Category], b)
+		}
+	}
+	type occurrence struct {
+		budget db.Budget
+		at     time.Time
+	}
+	var due []occurrence
+	seen := map[string]bool{}
+	for _, t := range touched {
+		for _, b := range byCategory[t.Category] {
+			p, err := b.ParsePeriod()
+			if err != nil {
+				return nil, err
+			}
+			start, end := p.Range(t.Date)
+			if !p.Recurring() && (t.Date.Before(start) || !t.Date.Before(end)) {
+				continue
+			}
+			key := fmt.Sprintf("%d|%s", b.ID, start.Format("2006-01-02"))
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			due = append(due, occurrence{b, t.Date})
+		}
+	}
+
+	var alerts []Alert
+	for _, o := range due {
+		a, ok, err := check(o.budget, o.at)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			alerts = append(alerts, a)
+		}
+	}
+
+	var notifyErrs []error
+	notifiers, err := ConfiguredNotifiers()
+	if err != nil {
+		notifyErrs = append(notifyErrs, err)
+	}
+	for _, a := range alerts {
+		if !a.New {
+			continue
+		}
+		for _, n := range notifiers {
+			if err := n.Notify(a); err != nil {
+				notifyErrs = append(notifyErrs, fmt.Errorf("%s: %w", n.Name(), err))
+			}
+		}
+	}
+	return alerts, errors.Join(notifyErrs...)
+}
+
+// check returns the alert for the occurrence of expense budget b containing
+// at, if any of its thresholds is crossed.
+func check(b db.Budget, at time.Time) (Alert, bool, error) {
+	occ, err := db.GetBudgetOccurrence(b, at)
+	if err != nil {
+		return Alert{}, false, err
+	}
+	if occ.Available <= 0 {
+		return Alert{}, false, nil
+	}
+	pct := occ.Actual / occ.Available * 100
+
+	a := Alert{
+		BudgetID:  b.ID,
+		Category:  b.Category,
+		From:      occ.From,
+		To:        occ.To,
+		PctUsed:   pct,
+		Spent:     occ.Actual,
+		Available: occ.Available,
+	}
+	crossed := false
+	for _, t := range b.Thresholds {
+		if pct < t {
+			break
+		}
+		crossed = true
+		a.Threshold = t
+		isNew, err := db.RecordBudgetAlert(b.ID, occ.From, t)
+		if err != nil {
+			return Alert{}, false, err
+		}
+		a.New = a.New || isNew
+	}
+	return a, crossed, nil
+}
+
+// TouchesOf builds touches for a batch of transactions.
+func TouchesOf(txs []db.Transaction) []Touch {
+	touched := make([]Touch, 0, len(txs))
+	for _, tx := range txs {
+		touched = append(touched, Touch{Category: tx.Category, Date: tx.Date})
+	}
+	return touched
+}
+
+// Warnings renders alerts and notifier errors as lines for CLI and TUI output.
+func Warnings(alerts []Alert, err error) []string {
+	var lines []string
+	for _, a := range alerts {
+		lines = append(lines, "Warning: "+a.Message())
+	}
+	if err != nil {
+		lines = append(lines, "Notification error: "+err.Error())
+	}
+	return lines
+}
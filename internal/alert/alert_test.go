@@ -0,0 +1,59 @@
+package alert
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"personal-finance-cli/db"
+)
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "alert-test")
+	if err != nil {
+		panic(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		panic(err)
+	}
+	if err := db.InitDB(); err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestEvaluateChecksEachPeriodOnce(t *testing.T) {
+	if err := db.InsertBudget(db.Budget{Category: "Food", Amount: 100, Period: "2026-10"}); err != nil {
+		t.Fatal(err)
+	}
+	var txs []db.Transaction
+	for _, d := range []string{"2026-09-30", "2026-10-02", "2026-10-09", "2026-10-16"} {
+		date, _ := time.Parse("2006-01-02", d)
+		tx := db.Transaction{Amount: -30, Description: "Groceries", Category: "Food", Date: date}
+		if err := db.InsertTransaction(tx); err != nil {
+			t.Fatal(err)
+		}
+		txs = append(txs, tx)
+	}
+
+	alerts, err := Evaluate(TouchesOf(txs)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("got %d alerts, want one for October", len(alerts))
+	}
+	if a := alerts[0]; a.From != "2026-10-01" || a.Threshold != 80 || a.Spent != 90 || !a.New {
+		t.Errorf("got %+v, want a new 80%% alert with 90 spent in October", a)
+	}
+
+	alerts, err = Evaluate(TouchesOf(txs)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 1 || alerts[0].New {
+		t.Errorf("second evaluation = %+v, want the same alert, no longer new", alerts)
+	}
+}
@@ -0,0 +1,197 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os/exec"
+	"strings"
+	"time"
+
+	"personal-finance-cli/db"
+)
+
+// Setting keys holding the notifier configuration. A notifier is enabled
+// when its key is non-empty.
+const (
+	CommandKey  = "alert.command"
+	WebhookKey  = "alert.webhook"
+	SMTPAddrKey = "alert.smtp.addr"
+	SMTPFromKey = "alert.smtp.from"
+	SMTPToKey   = "alert.smtp.to"
+	SMTPUserKey = "alert.smtp.user"
+	SMTPPassKey = "alert.smtp.password"
+)
+
+var SettingKeys = []string{CommandKey, WebhookKey, SMTPAddrKey, SMTPFromKey, SMTPToKey, SMTPUserKey, SMTPPassKey}
+
+// notifyTimeout bounds each notification so a hung command or server cannot
+// stall the CLI or freeze the TUI.
+const notifyTimeout = 10 * time.Second
+
+type Notifier interface {
+	Name() string
+	Notify(a Alert) error
+}
+
+// CommandNotifier runs a program with the alert message as its last
+// argument, e.g. "notify-send Budget".
+type CommandNotifier struct {
+	Command string
+}
+
+func (n CommandNotifier) Name() string { return "command" }
+
+func (n CommandNotifier) Notify(a Alert) error {
+	args := strings.Fields(n.Command)
+	if len(args) == 0 {
+		return fmt.Errorf("empty command")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+	if err := exec.CommandContext(ctx, args[0], append(args[1:], a.Message())...).Run(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("command timed out after %s", notifyTimeout)
+		}
+		return err
+	}
+	return nil
+}
+
+// WebhookNotifier POSTs the alert as JSON to URL.
+type WebhookNotifier struct {
+	URL string
+}
+
+func (n WebhookNotifier) Name() string { return "webhook" }
+
+func (n WebhookNotifier) Notify(a Alert) error {
+	body, err := json.Marshal(struct {
+		Alert
+		Message string `json:"message"`
+	}{a, a.Message()})
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: notifyTimeout}
+	resp, err := client.Post(n.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// EmailNotifier sends the alert through an SMTP server. Authentication is
+// only used when a user is configured, so a local relay or test stand-in
+// works without credentials.
+type EmailNotifier struct {
+	Addr     string
+	From     string
+	To       []string
+	User     string
+	Password string
+}
+
+func (n EmailNotifier) Name() string { return "email" }
+
+// Notify does what smtp.SendMail does (STARTTLS when offered, then
+// authentication) but over a connection with a deadline, which SendMail
+// has no way to set.
+func (n EmailNotifier) Notify(a Alert) error {
+	host := n.Addr
+	if i := strings.LastIndex(host, ":"); i >= 0 {
+		host = host[:i]
+	}
+	conn, err := (&net.Dialer{Timeout: notifyTimeout}).Dial("tcp", n.Addr)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(time.Now().Add(notifyTimeout)); err != nil {
+		conn.Close()
+		return err
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if n.User != "" {
+		if err := c.Auth(smtp.PlainAuth("", n.User, n.Password, host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(n.From); err != nil {
+		return err
+	}
+	for _, to := range n.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: Budget alert: %s\r\n\r\n%s\r\n",
+		n.From, strings.Join(n.To, ", "), a.Category, a.Message())
+	if _, err := w.Write([]byte(msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// ConfiguredNotifiers builds the notifiers enabled in settings.
+func ConfiguredNotifiers() ([]Notifier, error) {
+	cfg := map[string]string{}
+	for _, k := range SettingKeys {
+		v, err := db.GetSetting(k)
+		if err != nil {
+			return nil, err
+		}
+		cfg[k] = v
+	}
+
+	var notifiers []Notifier
+	if cfg[CommandKey] != "" {
+		notifiers = append(notifiers, CommandNotifier{Command: cfg[CommandKey]})
+	}
+	if cfg[WebhookKey] != "" {
+		notifiers = append(notifiers, WebhookNotifier{URL: cfg[WebhookKey]})
+	}
+	if cfg[SMTPAddrKey] != "" {
+		if cfg[SMTPFromKey] == "" || cfg[SMTPToKey] == "" {
+			return notifiers, fmt.Errorf("email alerts need %s and %s", SMTPFromKey, SMTPToKey)
+		}
+		var to []string
+		for _, addr := range strings.Split(cfg[SMTPToKey], ",") {
+			to = append(to, strings.TrimSpace(addr))
+		}
+		notifiers = append(notifiers, EmailNotifier{
+			Addr:     cfg[SMTPAddrKey],
+			From:     cfg[SMTPFromKey],
+			To:       to,
+			User:     cfg[SMTPUserKey],
+			Password: cfg[SMTPPassKey],
+		})
+	}
+	return notifiers, nil
+}
@@ -0,0 +1,205 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"personal-finance-cli/db"
+	"personal-finance-cli/internal/alert"
+	"personal-finance-cli/internal/parser"
+)
+
+const (
+	ArchiveDir = "archive"
+	FailedDir  = "failed"
+	LogFile    = "import.log"
+)
+
+type Result struct {
+	File       string
+	Imported   int
+	Duplicates int
+	// Warnings are budget alerts raised by the imported transactions.
+	Warnings []string
+	// Anomalies describe imported transactions that look unusual, such as
+	// double billing or a charge far above the usual amount.
+	Anomalies []string
+	Err       error
+}
+
+// ImportFile parses path and inserts every transaction that is not already
+// stored. Categories come from the parser's auto-categorization rules.
+func ImportFile(path string) Result {
+	res := Result{File: filepath.Base(path)}
+
+	parsed, err := parser.ParseFileByPath(path)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	if len(parsed) == 0 {
+		res.Err = fmt.Errorf("no transactions found")
+		return res
+	}
+	return ImportParsed(res.File, parsed)
+}
+
+// ImportParsed inserts the rows of parsed that are not already stored as one
+// import called name, undone in one step, and checks budgets and anomalies
+// for what was inserted.
+func ImportParsed(name string, parsed []parser.ParsedTransaction) Result {
+	res := Result{File: name}
+	var inserted []db.Transaction
+	defer db.SetOrigin(db.SetOrigin(db.OriginImport))
+	res.Err = db.Group("import "+name, func() error {
+		var err error
+		inserted, err = insertNew(parsed, &res)
+		return err
+	})
+	if res.Err != nil {
+		return res
+	}
+
+	res.Warnings = alert.Warnings(alert.Evaluate(alert.TouchesOf(inserted)...))
+	res.Anomalies = AnomalyMessages(db.AnomaliesAmong(inserted))
+	return res
+}
+
+// insertNew inserts the parsed rows that are not already stored and returns
+// them, counting imports and duplicates in res.
+func insertNew(parsed []parser.ParsedTransaction, res *Result) ([]db.Transaction, error) {
+	// stored holds, per date/amount/description, how many matching rows were
+	// in the database before this file; identical rows inside the same
+	// statement beyond that count are genuine repeats and get inserted.
+	stored := map[string]int{}
+	var inserted []db.Transaction
+	for _, p := range parsed {
+		tx := db.Transaction{
+			Amount:      p.Amount,
+			Description: p.Description,
+			Category:    p.Category,
+			Date:        p.Date,
+		}
+		key := db.MatchKey(tx)
+		n, seen := stored[key]
+		if !seen {
+			var err error
+			if n, err = db.CountMatchingTransactions(tx); err != nil {
+				return inserted, err
+			}
+		}
+		if n > 0 {
+			stored[key] = n - 1
+			res.Duplicates++
+			continue
+		}
+		stored[key] = 0
+		if err := db.InsertTransaction(tx); err != nil {
+			return inserted, err
+		}
+		res.Imported++
+		inserted = append(inserted, tx)
+	}
+	return inserted, nil
+}
+
+// AnomalyMessages formats the anomalies found in an import, or the error
+// that stopped the check.
+func AnomalyMessages(anomalies []db.Anomaly, err error) []string {
+	if err != nil {
+		return []string{fmt.Sprintf("Anomaly check failed: %v", err)}
+	}
+	msgs := make([]string, 0, len(anomalies))
+	for _, a := range anomalies {
+		msgs = append(msgs, a.Message())
+	}
+	return msgs
+}
+
+// Watcher imports statement files dropped into Dir, moving each one to
+// archive/ on success or failed/ on error and appending a line to import.log.
+type Watcher struct {
+	Dir string
+	// Settle is how long a file must stay unmodified before it is picked up,
+	// so half-downloaded statements are not imported.
+	Settle time.Duration
+	Out    io.Writer
+}
+
+// Scan processes every ready file currently in the inbox.
+func (w *Watcher) Scan() ([]Result, error) {
+	entries, err := os.ReadDir(w.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for _, e := range entries {
+		if e.IsDir() || !parser.IsSupportedFile(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || time.Since(info.ModTime()) < w.Settle {
+			continue
+		}
+
+		path := filepath.Join(w.Dir, e.Name())
+		res := ImportFile(path)
+
+		dest := ArchiveDir
+		if res.Err != nil {
+			dest = FailedDir
+		}
+		if err := w.move(path, dest); err != nil {
+			return results, err
+		}
+		if err := w.log(res); err != nil {
+			return results, err
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func (w *Watcher) move(path, sub string) error {
+	dir := filepath.Join(w.Dir, sub)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	target := filepath.Join(dir, filepath.Base(path))
+	if _, err := os.Stat(target); err == nil {
+		ext := filepath.Ext(path)
+		name := strings.TrimSuffix(filepath.Base(path), ext)
+		target = filepath.Join(dir, fmt.Sprintf("%s-%s%s", name, time.Now().Format("20060102-150405"), ext))
+	}
+	return os.Rename(path, target)
+}
+
+func (w *Watcher) log(res Result) error {
+	line := fmt.Sprintf("%s %s imported=%d duplicates=%d", time.Now().Format(time.RFC3339), res.File, res.Imported, res.Duplicates)
+	if res.Err != nil {
+		line = fmt.Sprintf("%s %s failed: %v", time.Now().Format(time.RFC3339), res.File, res.Err)
+	}
+
+	lines := append([]string{line}, res.Warnings...)
+	lines = append(lines, res.Anomalies...)
+
+	f, err := os.OpenFile(filepath.Join(w.Dir, LogFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	for _, l := range lines {
+		if _, err := fmt.Fprintln(f, l); err != nil {
+			return err
+		}
+		if w.Out != nil {
+			fmt.Fprintln(w.Out, l)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"personal-finance-cli/db"
+	"personal-finance-cli/internal/parser"
+)
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "importer-test")
+	if err != nil {
+		panic(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		panic(err)
+	}
+	if err := db.InitDB(); err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func writeStatement(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestImportFileSkipsStoredRows(t *testing.T) {
+	// The coffee appears twice on the same day: a genuine repeat within one
+	// statement that must be kept.
+	path := writeStatement(t, "dedupe.csv", "Date,Description,Amount\n"+
+		"2025-03-01,Dedupe coffee,-3.50\n"+
+		"2025-03-01,Dedupe coffee,-3.50\n"+
+		"2025-03-02,Dedupe groceries,-42.10\n")
+
+	first := ImportFile(path)
+	if first.Err != nil {
+		t.Fatal(first.Err)
+	}
+	if first.Imported != 3 || first.Duplicates != 0 {
+		t.Fatalf("first import: imported %d, duplicates %d; want 3, 0", first.Imported, first.Duplicates)
+	}
+
+	again := ImportFile(path)
+	if again.Err != nil {
+		t.Fatal(again.Err)
+	}
+	if again.Imported != 0 || again.Duplicates != 3 {
+		t.Fatalf("second import: imported %d, duplicates %d; want 0, 3", again.Imported, again.Duplicates)
+	}
+}
+
+func TestImportParsedAddsOnlyNewRepeats(t *testing.T) {
+	path := writeStatement(t, "repeat.csv", "Date,Description,Amount\n2025-04-05,Repeat parking,-2.00\n")
+	if res := ImportFile(path); res.Err != nil || res.Imported != 1 {
+		t.Fatalf("import: %+v", res)
+	}
+
+	// A later statement with the stored row plus a second identical charge
+	// keeps only the second one.
+	parsed, err := parser.ParseFileByPath(writeStatement(t, "repeat2.csv", "Date,Description,Amount\n"+
+		"2025-04-05,Repeat parking,-2.00\n"+
+		"2025-04-05,Repeat parking,-2.00\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := ImportParsed("repeat2.csv", parsed)
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	if res.Imported != 1 || res.Duplicates != 1 {
+		t.Fatalf("imported %d, duplicates %d; want 1, 1", res.Imported, res.Duplicates)
+	}
+}
+
+func TestImportMatchesAmountsToTheCent(t *testing.T) {
+	date, _ := time.Parse("2006-01-02", "2025-05-06")
+	if err := db.InsertTransaction(db.Transaction{Amount: -19.999, Description: "Cent lunch", Category: "Food", Date: date}); err != nil {
+		t.Fatal(err)
+	}
+
+	// -19.999 is stored as -20.00 to the cent, so the first row is that one
+	// and only the second is new.
+	path := writeStatement(t, "cents.csv", "Date,Description,Amount\n"+
+		"2025-05-06,Cent lunch,-20.00\n"+
+		"2025-05-06,Cent lunch,-20.00\n")
+	res := ImportFile(path)
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	if res.Imported != 1 || res.Duplicates != 1 {
+		t.Errorf("imported %d, duplicates %d; want 1, 1", res.Imported, res.Duplicates)
+	}
+}
@@ -12,8 +12,6 @@ import (
 	"strconv"
 	"strings"
 	"time"
-
-	"personal-finance-cli/db"
 )
 
 type ParsedTransaction struct {
@@ -23,6 +21,19 @@ type ParsedTransaction struct {
 	Category    string
 }
 
+// SupportedExtensions lists the file extensions DetectAndParse knows how to read.
+var SupportedExtensions = []string{".csv", ".ofx", ".qfx"}
+
+func IsSupportedFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range SupportedExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
 func DetectAndParse(r io.Reader, filename string) ([]ParsedTransaction, error) {
 	ext := strings.ToLower(filepath.Ext(filename))
 	switch ext {
@@ -132,7 +143,7 @@ func parseCSV(r io.Reader) ([]ParsedTransaction, error) {
 		}
 
 		if strings.TrimSpace(pt.Category) == "" {
-			pt.Category = inferCategory(pt.Description)
+			pt.Category = InferCategory(pt.Description)
 		}
 		parsed = append(parsed, pt)
 
@@ -192,7 +203,7 @@ func parseOFX(r io.Reader) ([]ParsedTransaction, error) {
 				Category:    "",
 			}
 			if strings.TrimSpace(pt.Category) == "" {
-				pt.Category = inferCategory(pt.Description)
+				pt.Category = InferCategory(pt.Description)
 			}
 			parsed = append(parsed, pt)
 			reset()
@@ -219,21 +230,6 @@ func parseOFX(r io.Reader) ([]ParsedTransaction, error) {
 	return parsed, nil
 }
 
-func InsertParsedTransactions(parsed []ParsedTransaction) error {
-	for _, p := range parsed {
-		tx := db.Transaction{
-			Amount:      p.Amount,
-			Description: p.Description,
-			Category:    p.Category,
-			Date:        p.Date,
-		}
-		if err := db.InsertTransaction(tx); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 // ------------------ Auto-categorization ------------------
 
 type rule struct {
@@ -252,7 +248,9 @@ var defaultRules = []rule{
 	{regexp.MustCompile(`\b(insurance)\b`), "Insurance"},
 }
 
-func inferCategory(description string) string {
+// InferCategory applies the auto-categorization rules to a description and
+// returns "Uncategorized" when none matches.
+func InferCategory(description string) string {
 	s := strings.ToLower(description)
 	for _, r := range defaultRules {
 		if r.re.MatchString(s) {
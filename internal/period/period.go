@@ -0,0 +1,124 @@
+package period
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout  = "2006-01-02"
+	monthLayout = "2006-01"
+	rangeSep    = ".."
+)
+
+type Kind int
+
+const (
+	// Month is one specific calendar month, written YYYY-MM.
+	Month Kind = iota
+	Weekly
+	Monthly
+	Quarterly
+	Yearly
+	// Custom is a fixed date range, written YYYY-MM-DD..YYYY-MM-DD (inclusive).
+	Custom
+)
+
+var recurringNames = map[string]Kind{
+	"weekly":    Weekly,
+	"monthly":   Monthly,
+	"quarterly": Quarterly,
+	"yearly":    Yearly,
+}
+
+// Period describes the time span a budget covers. Start and End are only
+// set for fixed periods (Month, Custom); End is exclusive.
+type Period struct {
+	Kind  Kind
+	Start time.Time
+	End   time.Time
+}
+
+// Parse accepts YYYY-MM, weekly, monthly, quarterly, yearly or
+// YYYY-MM-DD..YYYY-MM-DD.
+func Parse(s string) (Period, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if k, ok := recurringNames[s]; ok {
+		return Period{Kind: k}, nil
+	}
+
+	if from, to, ok := strings.Cut(s, rangeSep); ok {
+		start, err := time.Parse(dateLayout, strings.TrimSpace(from))
+		if err != nil {
+			return Period{}, fmt.Errorf("invalid period start %q, expected YYYY-MM-DD", from)
+		}
+		last, err := time.Parse(dateLayout, strings.TrimSpace(to))
+		if err != nil {
+			return Period{}, fmt.Errorf("invalid period end %q, expected YYYY-MM-DD", to)
+		}
+		if last.Before(start) {
+			return Period{}, fmt.Errorf("period end %s is before start %s", to, from)
+		}
+		return Period{Kind: Custom, Start: start, End: last.AddDate(0, 0, 1)}, nil
+	}
+
+	if m, err := time.Parse(monthLayout, s); err == nil {
+		return Period{Kind: Month, Start: m, End: m.AddDate(0, 1, 0)}, nil
+	}
+
+	return Period{}, fmt.Errorf("invalid period %q: use YYYY-MM, weekly, monthly, quarterly, yearly or YYYY-MM-DD..YYYY-MM-DD", s)
+}
+
+// MonthOf returns the specific-month period containing t.
+func MonthOf(t time.Time) Period {
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return Period{Kind: Month, Start: start, End: start.AddDate(0, 1, 0)}
+}
+
+func (p Period) Recurring() bool {
+	return p.Kind != Month && p.Kind != Custom
+}
+
+func (p Period) String() string {
+	switch p.Kind {
+	case Month:
+		return p.Start.Format(monthLayout)
+	case Custom:
+		return p.Start.Format(dateLayout) + rangeSep + p.End.AddDate(0, 0, -1).Format(dateLayout)
+	}
+	for name, k := range recurringNames {
+		if k == p.Kind {
+			return name
+		}
+	}
+	return ""
+}
+
+// Range returns the [start, end) dates the period covers. Recurring periods
+// resolve to the occurrence containing at; weeks start on Monday.
+func (p Period) Range(at time.Time) (time.Time, time.Time) {
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+	switch p.Kind {
+	case Weekly:
+		offset := (int(day.Weekday()) + 6) % 7
+		start := day.AddDate(0, 0, -offset)
+		return start, start.AddDate(0, 0, 7)
+	case Monthly:
+		start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	case Quarterly:
+		q := (int(day.Month()) - 1) / 3
+		start := time.Date(day.Year(), time.Month(q*3+1), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 3, 0)
+	case Yearly:
+		start := time.Date(day.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(1, 0, 0)
+	}
+	return p.Start, p.End
+}
+
+// Overlaps reports whether [start, end) intersects [from, to).
+func Overlaps(start, end, from, to time.Time) bool {
+	return start.Before(to) && from.Before(end)
+}
@@ -0,0 +1,96 @@
+package period
+
+import (
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	d, err := time.Parse(dateLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	tests := []struct {
+		in   string
+		kind Kind
+		want string
+	}{
+		{"2025-02", Month, "2025-02"},
+		{"weekly", Weekly, "weekly"},
+		{" Monthly ", Monthly, "monthly"},
+		{"quarterly", Quarterly, "quarterly"},
+		{"yearly", Yearly, "yearly"},
+		{"2025-01-10..2025-02-09", Custom, "2025-01-10..2025-02-09"},
+	}
+	for _, tt := range tests {
+		p, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if p.Kind != tt.kind || p.String() != tt.want {
+			t.Errorf("Parse(%q) = kind %d %q, want kind %d %q", tt.in, p.Kind, p.String(), tt.kind, tt.want)
+		}
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, in := range []string{"", "daily", "2025-13", "2025-02-10..2025-02-01", "2025-02-01..soon"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", in)
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		period     string
+		at         string
+		start, end string
+	}{
+		// 2025-03-05 is a Wednesday; weeks start on Monday.
+		{"weekly", "2025-03-05", "2025-03-03", "2025-03-10"},
+		{"weekly", "2025-03-03", "2025-03-03", "2025-03-10"},
+		{"weekly", "2025-03-09", "2025-03-03", "2025-03-10"},
+		{"monthly", "2024-02-29", "2024-02-01", "2024-03-01"},
+		{"quarterly", "2025-05-20", "2025-04-01", "2025-07-01"},
+		{"quarterly", "2025-12-31", "2025-10-01", "2026-01-01"},
+		{"yearly", "2025-06-15", "2025-01-01", "2026-01-01"},
+		// Fixed periods ignore at; the custom end is exclusive.
+		{"2025-02", "2030-01-01", "2025-02-01", "2025-03-01"},
+		{"2025-01-10..2025-02-09", "2025-01-01", "2025-01-10", "2025-02-10"},
+	}
+	for _, tt := range tests {
+		p, err := Parse(tt.period)
+		if err != nil {
+			t.Fatal(err)
+		}
+		start, end := p.Range(date(tt.at))
+		if !start.Equal(date(tt.start)) || !end.Equal(date(tt.end)) {
+			t.Errorf("%s at %s = %s..%s, want %s..%s", tt.period, tt.at,
+				start.Format(dateLayout), end.Format(dateLayout), tt.start, tt.end)
+		}
+	}
+}
+
+func TestRangeIgnoresTimeOfDay(t *testing.T) {
+	p, _ := Parse("monthly")
+	start, _ := p.Range(time.Date(2025, 3, 31, 23, 59, 0, 0, time.UTC))
+	if !start.Equal(date("2025-03-01")) {
+		t.Errorf("start = %s, want 2025-03-01", start)
+	}
+}
+
+func TestOverlaps(t *testing.T) {
+	jan, feb, mar := date("2025-01-01"), date("2025-02-01"), date("2025-03-01")
+	if !Overlaps(jan, mar, feb, mar) {
+		t.Error("January-February should overlap February")
+	}
+	if Overlaps(jan, feb, feb, mar) {
+		t.Error("ranges that only touch at the exclusive end should not overlap")
+	}
+}